@@ -0,0 +1,92 @@
+package hoop_watcher
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+const CacheTTLEnv = "HOOP_WATCHER_CACHE_TTL"
+
+// DefaultCacheTTL is how long cached highlights are served before searching
+// again.
+const DefaultCacheTTL = 6 * time.Hour
+
+// HighlightCache stores the highlights found for a game. found is false when
+// the game was not searched after fetchedAfter.
+type HighlightCache interface {
+	GetCachedHighlights(query HighlightQuery, fetchedAfter time.Time) (highlights []Highlight, found bool, err error)
+	CacheHighlights(query HighlightQuery, highlights []Highlight, fetchedAt time.Time) error
+}
+
+// CacheTTLFromEnv reads the cache TTL as a Go duration, e.g. 30m or 12h.
+func CacheTTLFromEnv() (time.Duration, error) {
+	ttlStr := os.Getenv(CacheTTLEnv)
+	if ttlStr == "" {
+		return DefaultCacheTTL, nil
+	}
+	ttl, err := time.ParseDuration(ttlStr)
+	if err != nil {
+		return DefaultCacheTTL, fmt.Errorf("Invalid %s %q", CacheTTLEnv, ttlStr)
+	}
+	return ttl, nil
+}
+
+// highlightCacheKey identifies the game of a query regardless of the order the
+// teams were given in. A single team has an opponent of 0 and the most recent
+// game has an empty date.
+func highlightCacheKey(query HighlightQuery) (teamId int, opponentId int, gameDate string) {
+	teamIds := []int{}
+	for _, team := range query.Teams {
+		teamIds = append(teamIds, team.Id)
+	}
+	sort.Ints(teamIds)
+	if len(teamIds) > 0 {
+		teamId = teamIds[0]
+	}
+	if len(teamIds) > 1 {
+		opponentId = teamIds[1]
+	}
+	if !query.Date.IsZero() {
+		gameDate = query.Date.Format(DAILY_DATE_FORMAT)
+	}
+	return teamId, opponentId, gameDate
+}
+
+// CachedHighlightProvider serves highlights from a cache, searching another
+// provider only when the cached results are missing or older than the TTL.
+// Search results are filtered and ranked for the query before they are cached.
+type CachedHighlightProvider struct {
+	provider HighlightProvider
+	cache    HighlightCache
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+func NewCachedHighlightProvider(provider HighlightProvider, cache HighlightCache, ttl time.Duration) *CachedHighlightProvider {
+	return &CachedHighlightProvider{provider: provider, cache: cache, ttl: ttl, now: time.Now}
+}
+
+func (p *CachedHighlightProvider) SearchHighlights(query HighlightQuery) ([]Highlight, error) {
+	now := p.now()
+	cached, found, err := p.cache.GetCachedHighlights(query, now.Add(-p.ttl))
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return cached, nil
+	}
+
+	highlights, err := p.provider.SearchHighlights(query)
+	if err != nil {
+		return nil, err
+	}
+	// Cache the ranked results, so the scores are stored and anything read
+	// straight from the cache is already filtered for the query.
+	highlights = filterHighlights(highlights, query)
+	if err := p.cache.CacheHighlights(query, highlights, now); err != nil {
+		return nil, err
+	}
+	return highlights, nil
+}
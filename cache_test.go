@@ -0,0 +1,189 @@
+package hoop_watcher_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+func newTestDB(t *testing.T) *hoop_watcher.SqliteHoopWatcherDB {
+	t.Helper()
+	db, err := hoop_watcher.NewSqliteHoopWatcherDB(filepath.Join(t.TempDir(), "hoop-watcher-test.db"))
+	if err != nil {
+		t.Fatalf("could not create test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.InitData(teamFilePath); err != nil {
+		t.Fatalf("could not init test db: %v", err)
+	}
+	return db
+}
+
+type countingHighlightProvider struct {
+	fakeHighlightProvider
+	calls int
+}
+
+func (c *countingHighlightProvider) SearchHighlights(query hoop_watcher.HighlightQuery) ([]hoop_watcher.Highlight, error) {
+	c.calls++
+	return c.fakeHighlightProvider.SearchHighlights(query)
+}
+
+func TestSqliteHighlightCache(t *testing.T) {
+	knicks := hoop_watcher.NBATeam{Id: 20, Name: "Knicks"}
+	heat := hoop_watcher.NBATeam{Id: 16, Name: "Heat"}
+	gameDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fetchedAt := time.Date(2023, time.January, 2, 12, 0, 0, 0, time.UTC)
+	highlight := hoop_watcher.Highlight{
+		Title:       "Knicks vs Heat Full Game Highlights",
+		URL:         mustParseURL(t, "https://www.youtube.com/watch?v=abc"),
+		Channel:     "NBA",
+		ChannelId:   "UCWJ2lWNubArHWmf3FIHbfcQ",
+		Trusted:     true,
+		PublishedAt: time.Date(2023, time.January, 2, 4, 0, 0, 0, time.UTC),
+		Score:       30,
+		Duration:    10 * time.Minute,
+		ViewCount:   1000,
+		Definition:  "hd",
+	}
+
+	t.Run("it serves cached highlights for the same game in either team order", func(t *testing.T) {
+		db := newTestDB(t)
+		query := hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks, heat}, Date: gameDate}
+		if err := db.CacheHighlights(query, []hoop_watcher.Highlight{highlight}, fetchedAt); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		reversed := hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{heat, knicks}, Date: gameDate}
+		got, found, err := db.GetCachedHighlights(reversed, fetchedAt.Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if !found || len(got) != 1 || !got[0].PublishedAt.Equal(highlight.PublishedAt) {
+			t.Fatalf("got %v, want %v", got, []hoop_watcher.Highlight{highlight})
+		}
+		got[0].PublishedAt = highlight.PublishedAt
+		if !reflect.DeepEqual(got[0], highlight) {
+			t.Errorf("got %v, want %v", got[0], highlight)
+		}
+
+		teamHighlights, err := db.GetTeamHighlights(heat.Id)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(teamHighlights) != 1 {
+			t.Errorf("got %d team highlights, want 1", len(teamHighlights))
+		}
+	})
+
+	t.Run("it ignores highlights fetched before the cutoff", func(t *testing.T) {
+		db := newTestDB(t)
+		query := hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}, Date: gameDate}
+		if err := db.CacheHighlights(query, []hoop_watcher.Highlight{highlight}, fetchedAt); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		got, found, err := db.GetCachedHighlights(query, fetchedAt.Add(time.Minute))
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if found || len(got) != 0 {
+			t.Errorf("got %v, %t, want a cache miss", got, found)
+		}
+	})
+
+	t.Run("it caches a search that found nothing", func(t *testing.T) {
+		db := newTestDB(t)
+		provider := &countingHighlightProvider{}
+		cached := hoop_watcher.NewCachedHighlightProvider(provider, db, time.Hour)
+		query := hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks, heat}, Date: gameDate}
+
+		for i := 0; i < 3; i++ {
+			got, err := cached.SearchHighlights(query)
+			if err != nil {
+				t.Fatalf("Found err: %v", err)
+			}
+			if len(got) != 0 {
+				t.Fatalf("got %v, want no highlights", got)
+			}
+		}
+		if provider.calls != 1 {
+			t.Errorf("got %d provider calls, want 1", provider.calls)
+		}
+
+		got, found, err := db.GetCachedHighlights(query, time.Now().Add(-time.Hour))
+		if err != nil || !found || len(got) != 0 {
+			t.Errorf("got %v, %t, %v, want a cached empty result", got, found, err)
+		}
+	})
+
+	t.Run("it caches the ranked results of the search", func(t *testing.T) {
+		db := newTestDB(t)
+		reaction := hoop_watcher.Highlight{Title: "Knicks vs Heat REACTION", URL: mustParseURL(t, "https://www.youtube.com/watch?v=react"), Duration: 5 * time.Minute}
+		condensed := hoop_watcher.Highlight{Title: "Knicks vs Heat Condensed Game", URL: mustParseURL(t, "https://www.youtube.com/watch?v=long"), Duration: 30 * time.Minute}
+		provider := &fakeHighlightProvider{highlights: []hoop_watcher.Highlight{reaction, condensed, highlight}}
+		cached := hoop_watcher.NewCachedHighlightProvider(provider, db, time.Hour)
+		query := hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks, heat}, Date: gameDate, Kind: hoop_watcher.RecapHighlights}
+
+		if _, err := hoop_watcher.FindHighlights(query, cached); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		got, found, err := db.GetCachedHighlights(query, time.Now().Add(-time.Hour))
+		if err != nil || !found {
+			t.Fatalf("got %v, %v, want cached highlights", found, err)
+		}
+		if len(got) != 2 || got[0].URL != highlight.URL || got[1].URL != reaction.URL {
+			t.Fatalf("got %v, want the recap then the reaction, without the condensed game", got)
+		}
+		if got[0].Score <= 0 || got[1].Score >= 0 {
+			t.Errorf("got scores %d and %d, want the recap to rank above zero and the reaction below", got[0].Score, got[1].Score)
+		}
+	})
+
+	t.Run("it only searches the provider on a cache miss", func(t *testing.T) {
+		db := newTestDB(t)
+		provider := &countingHighlightProvider{
+			fakeHighlightProvider: fakeHighlightProvider{highlights: []hoop_watcher.Highlight{highlight}},
+		}
+		cached := hoop_watcher.NewCachedHighlightProvider(provider, db, time.Hour)
+		query := hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks, heat}, Date: gameDate}
+
+		for i := 0; i < 3; i++ {
+			got, err := cached.SearchHighlights(query)
+			if err != nil {
+				t.Fatalf("Found err: %v", err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("got %v, want one highlight", got)
+			}
+		}
+		if provider.calls != 1 {
+			t.Errorf("got %d provider calls, want 1", provider.calls)
+		}
+
+		recapQuery := query
+		recapQuery.Kind = hoop_watcher.RecapHighlights
+		if _, err := cached.SearchHighlights(recapQuery); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if provider.calls != 2 {
+			t.Errorf("got %d provider calls, want a new search for another kind", provider.calls)
+		}
+	})
+
+	t.Run("it reads the TTL from the environment", func(t *testing.T) {
+		t.Setenv(hoop_watcher.CacheTTLEnv, "30m")
+		got, err := hoop_watcher.CacheTTLFromEnv()
+		if err != nil || got != 30*time.Minute {
+			t.Errorf("got %v, %v, want 30m", got, err)
+		}
+
+		t.Setenv(hoop_watcher.CacheTTLEnv, "soon")
+		if _, err := hoop_watcher.CacheTTLFromEnv(); err == nil {
+			t.Error("Expected error but err was nil")
+		}
+	})
+}
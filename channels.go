@@ -0,0 +1,93 @@
+package hoop_watcher
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	AllowedChannelsEnv     = "HOOP_WATCHER_ALLOWED_CHANNELS"
+	BlockedChannelsEnv     = "HOOP_WATCHER_BLOCKED_CHANNELS"
+	OnlyAllowedChannelsEnv = "HOOP_WATCHER_ONLY_ALLOWED_CHANNELS"
+)
+
+// DefaultAllowedChannelIds are the channels allowed when none are configured.
+var DefaultAllowedChannelIds = []string{
+	"UCWJ2lWNubArHWmf3FIHbfcQ", // NBA
+}
+
+// ChannelFilter decides which YouTube channels highlights may come from.
+// Blocked channels are dropped, allowed channels are marked as trusted, and
+// with RequireAllowed set every channel that is not allowed is dropped too.
+type ChannelFilter struct {
+	Allowed        []string
+	Blocked        []string
+	RequireAllowed bool
+}
+
+func splitChannelIds(channelIds string) []string {
+	ids := []string{}
+	for _, id := range strings.Split(channelIds, ",") {
+		if trimmedId := strings.TrimSpace(id); trimmedId != "" {
+			ids = append(ids, trimmedId)
+		}
+	}
+	return ids
+}
+
+// ChannelFilterFromEnv reads comma separated channel IDs from the allowed and
+// blocked channel environment variables.
+func ChannelFilterFromEnv() ChannelFilter {
+	allowed := DefaultAllowedChannelIds
+	if allowedEnv, ok := os.LookupEnv(AllowedChannelsEnv); ok {
+		allowed = splitChannelIds(allowedEnv)
+	}
+	return ChannelFilter{
+		Allowed:        allowed,
+		Blocked:        splitChannelIds(os.Getenv(BlockedChannelsEnv)),
+		RequireAllowed: os.Getenv(OnlyAllowedChannelsEnv) == "1",
+	}
+}
+
+func containsChannel(channelIds []string, channelId string) bool {
+	for _, id := range channelIds {
+		if id == channelId {
+			return true
+		}
+	}
+	return false
+}
+
+func (f ChannelFilter) Apply(highlights []Highlight) []Highlight {
+	filtered := []Highlight{}
+	for _, highlight := range highlights {
+		if containsChannel(f.Blocked, highlight.ChannelId) {
+			continue
+		}
+		highlight.Trusted = highlight.Trusted || containsChannel(f.Allowed, highlight.ChannelId)
+		if f.RequireAllowed && !highlight.Trusted {
+			continue
+		}
+		filtered = append(filtered, highlight)
+	}
+	return filtered
+}
+
+// ChannelFilteredHighlightProvider applies a ChannelFilter to the results of
+// another provider.
+type ChannelFilteredHighlightProvider struct {
+	provider HighlightProvider
+	filter   ChannelFilter
+}
+
+func NewChannelFilteredHighlightProvider(provider HighlightProvider, filter ChannelFilter) *ChannelFilteredHighlightProvider {
+	return &ChannelFilteredHighlightProvider{provider: provider, filter: filter}
+}
+
+func (p *ChannelFilteredHighlightProvider) SearchHighlights(query HighlightQuery) ([]Highlight, error) {
+	highlights, err := p.provider.SearchHighlights(query)
+	if err != nil {
+		return nil, err
+	}
+	return p.filter.Apply(highlights), nil
+}
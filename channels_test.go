@@ -0,0 +1,68 @@
+package hoop_watcher_test
+
+import (
+	"reflect"
+	"testing"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+func TestChannelFilter(t *testing.T) {
+	highlights := []hoop_watcher.Highlight{
+		{Title: "official", ChannelId: "official"},
+		{Title: "reupload", ChannelId: "reupload"},
+		{Title: "clickbait", ChannelId: "clickbait"},
+	}
+
+	t.Run("it drops blocked channels and trusts allowed ones", func(t *testing.T) {
+		filter := hoop_watcher.ChannelFilter{Allowed: []string{"official"}, Blocked: []string{"clickbait"}}
+		got := filter.Apply(highlights)
+		want := []hoop_watcher.Highlight{
+			{Title: "official", ChannelId: "official", Trusted: true},
+			{Title: "reupload", ChannelId: "reupload"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("it only keeps allowed channels when they are required", func(t *testing.T) {
+		filter := hoop_watcher.ChannelFilter{Allowed: []string{"official"}, RequireAllowed: true}
+		got := filter.Apply(highlights)
+		want := []hoop_watcher.Highlight{
+			{Title: "official", ChannelId: "official", Trusted: true},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("it reads channels from the environment", func(t *testing.T) {
+		t.Setenv(hoop_watcher.AllowedChannelsEnv, "a, b")
+		t.Setenv(hoop_watcher.BlockedChannelsEnv, "c")
+		t.Setenv(hoop_watcher.OnlyAllowedChannelsEnv, "1")
+		got := hoop_watcher.ChannelFilterFromEnv()
+		want := hoop_watcher.ChannelFilter{Allowed: []string{"a", "b"}, Blocked: []string{"c"}, RequireAllowed: true}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("it boosts allowed channels in the ranking", func(t *testing.T) {
+		knicks := hoop_watcher.NBATeam{Name: "Knicks", FullName: "New York Knicks", Abbreviation: "NYK"}
+		provider := hoop_watcher.NewChannelFilteredHighlightProvider(
+			&fakeHighlightProvider{highlights: []hoop_watcher.Highlight{
+				{Title: "Knicks Highlights", ChannelId: "reupload"},
+				{Title: "Knicks Highlights", ChannelId: "official"},
+			}},
+			hoop_watcher.ChannelFilter{Allowed: []string{"official"}},
+		)
+		got, err := hoop_watcher.GetHighlightsForTUI(hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}}, provider)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 2 || got[0].ChannelId != "official" {
+			t.Errorf("got %v, want the allowed channel first", got)
+		}
+	})
+}
@@ -0,0 +1,283 @@
+// Package client calls the hoop-watcher-server HTTP API.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+// ServerEnv holds the URL of the hoop-watcher-server to use, e.g.
+// http://localhost:8080.
+const ServerEnv = "HOOP_WATCHER_SERVER"
+
+const (
+	defaultTimeout    = 30 * time.Second
+	defaultMaxRetries = 3
+	defaultBackoff    = 500 * time.Millisecond
+	maxBackoff        = 10 * time.Second
+)
+
+// maxErrorBodySize caps how much of an error response is read into an error.
+const maxErrorBodySize = 1024
+
+// Client calls the API of a hoop-watcher-server. Requests that fail with a
+// network error or a temporary status are retried MaxRetries times, waiting
+// Backoff before the first retry and twice as long before each one after.
+type Client struct {
+	HTTPClient *http.Client
+	MaxRetries int
+	Backoff    time.Duration
+	baseURL    *url.URL
+}
+
+// New returns a client for the server at baseURL.
+func New(baseURL string) (*Client, error) {
+	parsedURL, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, fmt.Errorf("Invalid server URL %q, expected e.g. http://localhost:8080", baseURL)
+	}
+	parsedURL.Path = strings.TrimSuffix(parsedURL.Path, "/")
+	return &Client{
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+		MaxRetries: defaultMaxRetries,
+		Backoff:    defaultBackoff,
+		baseURL:    parsedURL,
+	}, nil
+}
+
+// backoff is how long to wait before the retry after attempt.
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.Backoff << attempt
+	if wait > maxBackoff || wait <= 0 {
+		return maxBackoff
+	}
+	return wait
+}
+
+// do sends a request to the escaped path, retrying temporary failures, and
+// decodes the JSON response into v unless v is nil.
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, v interface{}) error {
+	for attempt := 0; ; attempt++ {
+		retry, err := c.doOnce(ctx, method, path, query, v)
+		if err == nil || !retry || attempt >= c.MaxRetries {
+			return err
+		}
+		timer := time.NewTimer(c.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// doOnce sends a single request, reporting whether it is worth retrying when
+// it fails.
+func (c *Client) doOnce(ctx context.Context, method string, path string, query url.Values, v interface{}) (retry bool, err error) {
+	reqURL := c.baseURL.JoinPath(path)
+	reqURL.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("Error occurred calling %s: %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		apiErr := newAPIError(resp.StatusCode, resp.Header, body)
+		return apiErr.temporary(), apiErr
+	}
+	if v == nil {
+		return false, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("Error occurred decoding response of %s: %w", path, err)
+	}
+	return false, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	return c.do(ctx, http.MethodGet, path, query, v)
+}
+
+// teamPath is the path of team, which may be an abbreviation, name or alias.
+func teamPath(team string) string {
+	return "/teams/" + url.PathEscape(team)
+}
+
+// ListTeams returns every team the server knows.
+func (c *Client) ListTeams(ctx context.Context) ([]hoop_watcher.NBATeam, error) {
+	teams := []hoop_watcher.NBATeam{}
+	if err := c.get(ctx, "/teams", nil, &teams); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// GetTeam returns the team with the abbreviation, name or alias team.
+// Returns an error wrapping ErrNotFound if there is none.
+func (c *Client) GetTeam(ctx context.Context, team string) (hoop_watcher.NBATeam, error) {
+	var nbaTeam hoop_watcher.NBATeam
+	if err := c.get(ctx, teamPath(team), nil, &nbaTeam); err != nil {
+		return hoop_watcher.NBATeam{}, err
+	}
+	return nbaTeam, nil
+}
+
+// HighlightOptions narrows down the highlights of a team's game.
+type HighlightOptions struct {
+	// Date is the day of the game, zero for the team's most recent game.
+	Date time.Time
+	// Opponent is the other team's abbreviation, name or alias. The server
+	// uses the scheduled opponent when it is empty.
+	Opponent string
+	Kind     hoop_watcher.HighlightKind
+	// Limit caps the number of highlights returned, 0 for no limit.
+	Limit int
+}
+
+func (o HighlightOptions) values() url.Values {
+	values := url.Values{}
+	if !o.Date.IsZero() {
+		values.Set("date", o.Date.Format(hoop_watcher.DAILY_DATE_FORMAT))
+	}
+	if o.Opponent != "" {
+		values.Set("opponent", o.Opponent)
+	}
+	if o.Kind != hoop_watcher.AnyHighlights {
+		values.Set("kind", string(o.Kind))
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return values
+}
+
+// GetHighlights returns the highlights of team's game on opts.Date, or its
+// most recent game, best match first. team may be an abbreviation, name or alias.
+func (c *Client) GetHighlights(ctx context.Context, team string, opts HighlightOptions) ([]hoop_watcher.Highlight, error) {
+	highlights := []hoop_watcher.Highlight{}
+	if err := c.get(ctx, teamPath(team)+"/highlights", opts.values(), &highlights); err != nil {
+		return nil, err
+	}
+	return highlights, nil
+}
+
+// GameOptions narrows down the games to list. Zero fields are left to the
+// server's defaults.
+type GameOptions struct {
+	// Date lists a single day's games, instead of From and To.
+	Date time.Time
+	// From and To are the first and last dates to include.
+	From time.Time
+	To   time.Time
+	// Limit is the page size and Offset the number of games to skip.
+	Limit  int
+	Offset int
+}
+
+func (o GameOptions) values() url.Values {
+	values := url.Values{}
+	for name, date := range map[string]time.Time{"date": o.Date, "from": o.From, "to": o.To} {
+		if !date.IsZero() {
+			values.Set(name, date.Format(hoop_watcher.DAILY_DATE_FORMAT))
+		}
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		values.Set("offset", strconv.Itoa(o.Offset))
+	}
+	return values
+}
+
+// ListGames returns a page of the games matching opts with their
+// highlights, earliest first.
+func (c *Client) ListGames(ctx context.Context, opts GameOptions) ([]hoop_watcher.GameWithHighlights, error) {
+	games := []hoop_watcher.GameWithHighlights{}
+	if err := c.get(ctx, "/games", opts.values(), &games); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+// ListTeamGames returns a page of team's games matching opts, like ListGames.
+func (c *Client) ListTeamGames(ctx context.Context, team string, opts GameOptions) ([]hoop_watcher.GameWithHighlights, error) {
+	games := []hoop_watcher.GameWithHighlights{}
+	if err := c.get(ctx, teamPath(team)+"/games", opts.values(), &games); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+// GetGame returns the game with id and its highlights. Returns an error
+// wrapping ErrNotFound if there is none.
+func (c *Client) GetGame(ctx context.Context, id int) (hoop_watcher.GameWithHighlights, error) {
+	var game hoop_watcher.GameWithHighlights
+	if err := c.get(ctx, "/games/"+strconv.Itoa(id), nil, &game); err != nil {
+		return hoop_watcher.GameWithHighlights{}, err
+	}
+	return game, nil
+}
+
+// ListFavoriteTeams returns the teams marked as favorites on the server.
+func (c *Client) ListFavoriteTeams(ctx context.Context) ([]hoop_watcher.NBATeam, error) {
+	teams := []hoop_watcher.NBATeam{}
+	if err := c.get(ctx, "/teams/favorites", nil, &teams); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// FavoriteTeam marks team as a favorite. Returns an error wrapping
+// ErrNotFound if there is no such team.
+func (c *Client) FavoriteTeam(ctx context.Context, team string) error {
+	return c.do(ctx, http.MethodPut, teamPath(team)+"/favorite", nil, nil)
+}
+
+// UnfavoriteTeam removes team from the favorites. Returns an error wrapping
+// ErrNotFound if there is no such team.
+func (c *Client) UnfavoriteTeam(ctx context.Context, team string) error {
+	return c.do(ctx, http.MethodDelete, teamPath(team)+"/favorite", nil, nil)
+}
+
+// HighlightProvider searches highlights through the server, sharing its
+// YouTube key and cache.
+func (c *Client) HighlightProvider() hoop_watcher.HighlightProvider {
+	return highlightProvider{client: c}
+}
+
+type highlightProvider struct {
+	client *Client
+}
+
+// SearchHighlights asks the server for the highlights of the query's first
+// team against its second. A zero date leaves the server to search the most
+// recent game, as other providers do.
+func (p highlightProvider) SearchHighlights(query hoop_watcher.HighlightQuery) ([]hoop_watcher.Highlight, error) {
+	if len(query.Teams) == 0 {
+		return nil, errors.New("Missing team to search highlights for")
+	}
+	opts := HighlightOptions{Date: query.Date, Kind: query.Kind}
+	if len(query.Teams) > 1 {
+		opts.Opponent = query.Teams[1].Abbreviation
+	}
+	return p.client.GetHighlights(context.Background(), query.Teams[0].Abbreviation, opts)
+}
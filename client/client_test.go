@@ -0,0 +1,230 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+	"github.com/WesleyT4N/hoop-watcher-cli/client"
+)
+
+var (
+	knicks = hoop_watcher.NBATeam{Id: 20, Name: "Knicks", FullName: "New York Knicks", Abbreviation: "NYK", City: "New York"}
+	heat   = hoop_watcher.NBATeam{Id: 16, Name: "Heat", FullName: "Miami Heat", Abbreviation: "MIA", City: "Miami"}
+)
+
+// newTestServer serves body as JSON and records the requests it gets.
+func newTestServer(t *testing.T, status int, body interface{}) (*client.Client, *[]*http.Request) {
+	t.Helper()
+	requests := []*http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if status != http.StatusOK {
+			http.Error(w, body.(string), status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(server.URL + "/")
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+	c.Backoff = time.Millisecond
+	return c, &requests
+}
+
+func TestNew(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "ftp://localhost", "http://"} {
+		if _, err := client.New(baseURL); err == nil {
+			t.Errorf("got no err for %q, want an invalid server URL error", baseURL)
+		}
+	}
+}
+
+func TestListTeams(t *testing.T) {
+	c, requests := newTestServer(t, http.StatusOK, []hoop_watcher.NBATeam{knicks, heat})
+
+	got, err := c.ListTeams(context.Background())
+	if err != nil {
+		t.Fatalf("Found err: %v", err)
+	}
+	if want := []hoop_watcher.NBATeam{knicks, heat}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if path := (*requests)[0].URL.Path; path != "/teams" {
+		t.Errorf("got path %s, want /teams", path)
+	}
+}
+
+func TestGetHighlights(t *testing.T) {
+	highlight := hoop_watcher.Highlight{
+		Title:       "Knicks vs Heat Full Game Highlights",
+		URL:         url.URL{Scheme: "https", Host: "www.youtube.com", Path: "/watch", RawQuery: "v=abc"},
+		Channel:     "NBA",
+		Trusted:     true,
+		PublishedAt: time.Date(2024, time.January, 2, 4, 0, 0, 0, time.UTC),
+		Duration:    10 * time.Minute,
+	}
+
+	t.Run("it requests the team's highlights with the options", func(t *testing.T) {
+		c, requests := newTestServer(t, http.StatusOK, []hoop_watcher.Highlight{highlight})
+
+		got, err := c.GetHighlights(context.Background(), "NYK", client.HighlightOptions{
+			Date:     time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Opponent: "MIA",
+			Kind:     hoop_watcher.RecapHighlights,
+			Limit:    5,
+		})
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if want := []hoop_watcher.Highlight{highlight}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		req := (*requests)[0]
+		if req.URL.Path != "/teams/NYK/highlights" {
+			t.Errorf("got path %s, want /teams/NYK/highlights", req.URL.Path)
+		}
+		want := url.Values{"date": {"2024-01-01"}, "opponent": {"MIA"}, "kind": {"recap"}, "limit": {"5"}}
+		if got := req.URL.Query(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got query %v, want %v", got, want)
+		}
+	})
+
+	t.Run("it returns the server's error after retrying", func(t *testing.T) {
+		c, requests := newTestServer(t, http.StatusServiceUnavailable, "Highlights Unavailable")
+
+		_, err := c.GetHighlights(context.Background(), "NYK", client.HighlightOptions{Date: time.Now()})
+		if !errors.Is(err, client.ErrUnavailable) {
+			t.Fatalf("got err %v, want %v", err, client.ErrUnavailable)
+		}
+		if want := "Server responded 503 Service Unavailable: Highlights Unavailable"; err.Error() != want {
+			t.Errorf("got %q, want %q", err.Error(), want)
+		}
+		if got, want := len(*requests), c.MaxRetries+1; got != want {
+			t.Errorf("got %d requests, want %d", got, want)
+		}
+	})
+
+	t.Run("it does not retry running out of quota", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Retry-After", "3600")
+			http.Error(w, "Highlight search quota exceeded", http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+		c, err := client.New(server.URL)
+		if err != nil {
+			t.Fatalf("could not create client: %v", err)
+		}
+		c.Backoff = time.Millisecond
+
+		_, err = c.GetHighlights(context.Background(), "NYK", client.HighlightOptions{Date: time.Now()})
+		var apiErr *client.APIError
+		if !errors.Is(err, client.ErrQuotaExceeded) || !errors.As(err, &apiErr) {
+			t.Fatalf("got err %v, want %v", err, client.ErrQuotaExceeded)
+		}
+		if apiErr.RetryAfter != time.Hour {
+			t.Errorf("got Retry-After %v, want %v", apiErr.RetryAfter, time.Hour)
+		}
+		if requests != 1 {
+			t.Errorf("got %d requests, want 1", requests)
+		}
+	})
+}
+
+func TestHighlightProvider(t *testing.T) {
+	t.Run("it searches the first team's game against the second", func(t *testing.T) {
+		c, requests := newTestServer(t, http.StatusOK, []hoop_watcher.Highlight{})
+		query := hoop_watcher.HighlightQuery{
+			Teams: []hoop_watcher.NBATeam{knicks, heat},
+			Date:  time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}
+
+		if _, err := c.HighlightProvider().SearchHighlights(query); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		req := (*requests)[0]
+		if req.URL.Path != "/teams/NYK/highlights" {
+			t.Errorf("got path %s, want /teams/NYK/highlights", req.URL.Path)
+		}
+		want := url.Values{"date": {"2024-01-01"}, "opponent": {"MIA"}}
+		if got := req.URL.Query(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got query %v, want %v", got, want)
+		}
+	})
+
+	t.Run("it leaves the most recent game to the server without a date", func(t *testing.T) {
+		c, requests := newTestServer(t, http.StatusOK, []hoop_watcher.Highlight{})
+		query := hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}}
+
+		if _, err := c.HighlightProvider().SearchHighlights(query); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if got := (*requests)[0].URL.Query(); got.Has("date") {
+			t.Errorf("got query %v, want no date", got)
+		}
+	})
+}
+
+func TestRetries(t *testing.T) {
+	t.Run("it retries temporary failures until one succeeds", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 3 {
+				http.Error(w, "Bad Gateway", http.StatusBadGateway)
+				return
+			}
+			json.NewEncoder(w).Encode([]hoop_watcher.NBATeam{knicks})
+		}))
+		t.Cleanup(server.Close)
+		c, _ := client.New(server.URL)
+		c.Backoff = time.Millisecond
+
+		got, err := c.ListTeams(context.Background())
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 1 || attempts != 3 {
+			t.Errorf("got %v after %d attempts, want the Knicks after 3", got, attempts)
+		}
+	})
+
+	t.Run("it does not retry client errors", func(t *testing.T) {
+		c, requests := newTestServer(t, http.StatusNotFound, "No results found")
+
+		_, err := c.GetTeam(context.Background(), "SEA")
+		if !errors.Is(err, client.ErrNotFound) {
+			t.Fatalf("got err %v, want %v", err, client.ErrNotFound)
+		}
+		if len(*requests) != 1 {
+			t.Errorf("got %d requests, want 1", len(*requests))
+		}
+	})
+
+	t.Run("it stops waiting when the context is done", func(t *testing.T) {
+		c, requests := newTestServer(t, http.StatusServiceUnavailable, "Highlights Unavailable")
+		c.Backoff = time.Hour
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if _, err := c.ListTeams(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("got err %v, want %v", err, context.DeadlineExceeded)
+		}
+		if len(*requests) != 1 {
+			t.Errorf("got %d requests, want 1", len(*requests))
+		}
+	})
+}
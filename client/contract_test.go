@@ -0,0 +1,344 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+	"github.com/WesleyT4N/hoop-watcher-cli/client"
+)
+
+// The contract tests run the client against the server's handlers, so a
+// change to either side that breaks the other fails here.
+
+var teamFilePath = filepath.Join("..", hoop_watcher.TeamFileName)
+
+type fakeHighlightProvider struct {
+	highlights []hoop_watcher.Highlight
+	err        error
+	gotQuery   hoop_watcher.HighlightQuery
+}
+
+func (f *fakeHighlightProvider) SearchHighlights(query hoop_watcher.HighlightQuery) ([]hoop_watcher.Highlight, error) {
+	f.gotQuery = query
+	return f.highlights, f.err
+}
+
+// newContractServer serves the API over a fresh SQLite database.
+func newContractServer(t *testing.T, provider hoop_watcher.HighlightProvider) (*client.Client, *hoop_watcher.SqliteHoopWatcherDB) {
+	t.Helper()
+	db, err := hoop_watcher.NewSqliteHoopWatcherDB(filepath.Join(t.TempDir(), "hoop-watcher-test.db"))
+	if err != nil {
+		t.Fatalf("could not create test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.InitData(teamFilePath); err != nil {
+		t.Fatalf("could not init test db: %v", err)
+	}
+
+	router := http.NewServeMux()
+	hoop_watcher.NewBaseHandler(db, provider).RegisterRoutes(router)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	c, err := client.New(server.URL)
+	if err != nil {
+		t.Fatalf("could not create client: %v", err)
+	}
+	c.Backoff = time.Millisecond
+	return c, db
+}
+
+func TestContractTeams(t *testing.T) {
+	c, db := newContractServer(t, nil)
+	ctx := context.Background()
+
+	t.Run("ListTeams returns every team", func(t *testing.T) {
+		got, err := c.ListTeams(ctx)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		want, _ := db.GetAllTeams()
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("GetTeam resolves names and aliases", func(t *testing.T) {
+		for _, team := range []string{"NYK", "knicks", "New York Knicks"} {
+			got, err := c.GetTeam(ctx, team)
+			if err != nil {
+				t.Fatalf("Found err for %q: %v", team, err)
+			}
+			if got.Abbreviation != "NYK" {
+				t.Errorf("got %s for %q, want NYK", got.Abbreviation, team)
+			}
+		}
+	})
+
+	t.Run("GetTeam returns ErrNotFound for an unknown team", func(t *testing.T) {
+		if _, err := c.GetTeam(ctx, "SEA"); !errors.Is(err, client.ErrNotFound) {
+			t.Errorf("got err %v, want %v", err, client.ErrNotFound)
+		}
+	})
+
+	t.Run("favorite mutations show up in ListFavoriteTeams", func(t *testing.T) {
+		if err := c.FavoriteTeam(ctx, "NYK"); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		got, err := c.ListFavoriteTeams(ctx)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 1 || got[0].Abbreviation != "NYK" {
+			t.Errorf("got %v, want the Knicks", got)
+		}
+
+		if err := c.UnfavoriteTeam(ctx, "NYK"); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if got, _ := c.ListFavoriteTeams(ctx); len(got) != 0 {
+			t.Errorf("got %v, want no favorites", got)
+		}
+		if err := c.FavoriteTeam(ctx, "SEA"); !errors.Is(err, client.ErrNotFound) {
+			t.Errorf("got err %v, want %v", err, client.ErrNotFound)
+		}
+	})
+
+	t.Run("a database error is ErrServer", func(t *testing.T) {
+		db.Close()
+		if _, err := c.ListTeams(ctx); !errors.Is(err, client.ErrServer) {
+			t.Errorf("got err %v, want %v", err, client.ErrServer)
+		}
+	})
+}
+
+func TestContractHighlights(t *testing.T) {
+	ctx := context.Background()
+	date := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	highlight := hoop_watcher.Highlight{
+		Title:       "Knicks vs Heat Full Game Highlights",
+		URL:         url.URL{Scheme: "https", Host: "www.youtube.com", Path: "/watch", RawQuery: "v=abc"},
+		Channel:     "NBA",
+		Trusted:     true,
+		PublishedAt: date.Add(26 * time.Hour),
+		Duration:    10 * time.Minute,
+	}
+
+	t.Run("GetHighlights searches the game against the opponent", func(t *testing.T) {
+		provider := &fakeHighlightProvider{highlights: []hoop_watcher.Highlight{highlight, highlight}}
+		c, _ := newContractServer(t, provider)
+
+		got, err := c.GetHighlights(ctx, "NYK", client.HighlightOptions{Date: date, Opponent: "heat", Limit: 1})
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 1 || got[0].URL != highlight.URL || got[0].Title != highlight.Title || got[0].Duration != highlight.Duration {
+			t.Errorf("got %v, want %v", got, highlight)
+		}
+		teams := []string{}
+		for _, team := range provider.gotQuery.Teams {
+			teams = append(teams, team.Abbreviation)
+		}
+		if want := []string{"NYK", "MIA"}; !reflect.DeepEqual(teams, want) || !provider.gotQuery.Date.Equal(date) {
+			t.Errorf("got %v on %v, want %v on %v", teams, provider.gotQuery.Date, want, date)
+		}
+	})
+
+	t.Run("invalid options are ErrBadRequest", func(t *testing.T) {
+		c, _ := newContractServer(t, &fakeHighlightProvider{})
+		for _, opts := range []client.HighlightOptions{
+			{Date: date, Kind: hoop_watcher.HighlightKind("bloopers")},
+			{Date: date, Opponent: "NYK"},
+		} {
+			if _, err := c.GetHighlights(ctx, "NYK", opts); !errors.Is(err, client.ErrBadRequest) {
+				t.Errorf("got err %v for %+v, want %v", err, opts, client.ErrBadRequest)
+			}
+		}
+	})
+
+	t.Run("a validation error names the parameter", func(t *testing.T) {
+		c, _ := newContractServer(t, &fakeHighlightProvider{})
+		_, err := c.GetHighlights(ctx, "NYK", client.HighlightOptions{Date: date, Kind: hoop_watcher.HighlightKind("bloopers")})
+		var apiErr *client.APIError
+		if !errors.As(err, &apiErr) || apiErr.Parameter != "kind" || apiErr.Message != "Invalid kind query parameter" {
+			t.Errorf("got err %v, want an invalid kind", err)
+		}
+	})
+
+	t.Run("provider failures are ErrUnavailable", func(t *testing.T) {
+		c, _ := newContractServer(t, nil)
+		if _, err := c.GetHighlights(ctx, "NYK", client.HighlightOptions{Date: date}); !errors.Is(err, client.ErrUnavailable) {
+			t.Errorf("got err %v, want %v", err, client.ErrUnavailable)
+		}
+	})
+
+	t.Run("running out of quota is ErrQuotaExceeded", func(t *testing.T) {
+		c, _ := newContractServer(t, &fakeHighlightProvider{err: hoop_watcher.ErrQuotaExceeded})
+		_, err := c.GetHighlights(ctx, "NYK", client.HighlightOptions{Date: date})
+		var apiErr *client.APIError
+		if !errors.Is(err, client.ErrQuotaExceeded) || !errors.As(err, &apiErr) || apiErr.RetryAfter <= 0 {
+			t.Errorf("got err %v, want %v with a Retry-After", err, client.ErrQuotaExceeded)
+		}
+	})
+}
+
+func TestContractGames(t *testing.T) {
+	c, db := newContractServer(t, nil)
+	ctx := context.Background()
+	knicks, _ := db.GetTeamByAbbrev("NYK")
+	heat, _ := db.GetTeamByAbbrev("MIA")
+	lakers, _ := db.GetTeamByAbbrev("LAL")
+	date := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	game, err := db.AddGame(hoop_watcher.Game{HomeTeam: heat, AwayTeam: knicks, Date: date})
+	if err != nil {
+		t.Fatalf("could not add game: %v", err)
+	}
+	later, err := db.AddGame(hoop_watcher.Game{HomeTeam: lakers, AwayTeam: heat, Date: date.AddDate(0, 0, 2)})
+	if err != nil {
+		t.Fatalf("could not add game: %v", err)
+	}
+	highlight := hoop_watcher.Highlight{
+		Title: "Knicks vs Heat Full Game Highlights",
+		URL:   url.URL{Scheme: "https", Host: "www.youtube.com", Path: "/watch", RawQuery: "v=abc"},
+	}
+	if err := db.CacheHighlights(game.HighlightQuery(hoop_watcher.AnyHighlights), []hoop_watcher.Highlight{highlight}, time.Now()); err != nil {
+		t.Fatalf("could not cache highlights: %v", err)
+	}
+
+	gameIds := func(games []hoop_watcher.GameWithHighlights) []int {
+		ids := []int{}
+		for _, game := range games {
+			ids = append(ids, game.Id)
+		}
+		return ids
+	}
+
+	t.Run("ListGames returns the games on the date with their highlights", func(t *testing.T) {
+		got, err := c.ListGames(ctx, client.GameOptions{Date: date})
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 1 || got[0].Id != game.Id || got[0].String() != "NYK @ MIA" || !got[0].Date.Equal(game.Date) {
+			t.Fatalf("got %v, want %v", got, game)
+		}
+		if len(got[0].Highlights) != 1 || got[0].Highlights[0].URL != highlight.URL {
+			t.Errorf("got highlights %v, want %v", got[0].Highlights, highlight)
+		}
+	})
+
+	t.Run("ListGames filters and pages", func(t *testing.T) {
+		cases := map[string]struct {
+			opts client.GameOptions
+			want []int
+		}{
+			"everything":   {client.GameOptions{}, []int{game.Id, later.Id}},
+			"a date range": {client.GameOptions{From: date.AddDate(0, 0, 1), To: date.AddDate(0, 0, 7)}, []int{later.Id}},
+			"a page":       {client.GameOptions{Limit: 1, Offset: 1}, []int{later.Id}},
+			"no games":     {client.GameOptions{Date: date.AddDate(0, 0, 1)}, []int{}},
+		}
+		for name, tc := range cases {
+			got, err := c.ListGames(ctx, tc.opts)
+			if err != nil {
+				t.Fatalf("Found err for %s: %v", name, err)
+			}
+			if ids := gameIds(got); !reflect.DeepEqual(ids, tc.want) {
+				t.Errorf("got %v for %s, want %v", ids, name, tc.want)
+			}
+		}
+		if _, err := c.ListGames(ctx, client.GameOptions{From: date, To: date.AddDate(0, 0, -1)}); !errors.Is(err, client.ErrBadRequest) {
+			t.Errorf("got err %v, want %v", err, client.ErrBadRequest)
+		}
+	})
+
+	t.Run("ListTeamGames returns the team's games", func(t *testing.T) {
+		got, err := c.ListTeamGames(ctx, "lakers", client.GameOptions{})
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if ids := gameIds(got); !reflect.DeepEqual(ids, []int{later.Id}) {
+			t.Errorf("got %v, want %v", ids, []int{later.Id})
+		}
+		if _, err := c.ListTeamGames(ctx, "SEA", client.GameOptions{}); !errors.Is(err, client.ErrNotFound) {
+			t.Errorf("got err %v, want %v", err, client.ErrNotFound)
+		}
+	})
+
+	t.Run("GetGame returns the game with its highlights", func(t *testing.T) {
+		got, err := c.GetGame(ctx, game.Id)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if got.Id != game.Id || len(got.Highlights) != 1 {
+			t.Errorf("got %v, want %v with its highlight", got, game)
+		}
+		if _, err := c.GetGame(ctx, later.Id+1); !errors.Is(err, client.ErrNotFound) {
+			t.Errorf("got err %v, want %v", err, client.ErrNotFound)
+		}
+	})
+}
+
+func TestContractStore(t *testing.T) {
+	c, db := newContractServer(t, nil)
+	store := c.Store()
+	knicks, _ := db.GetTeamByAbbrev("NYK")
+	heat, _ := db.GetTeamByAbbrev("MIA")
+	openingNight := time.Date(2023, time.October, 24, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < hoop_watcher.MaxGamesLimit+1; i++ {
+		game := hoop_watcher.Game{HomeTeam: heat, AwayTeam: knicks, Date: openingNight.AddDate(0, 0, i)}
+		if _, err := db.AddGame(game); err != nil {
+			t.Fatalf("could not add game: %v", err)
+		}
+	}
+
+	t.Run("it looks up teams, wrapping ErrTeamNotFound", func(t *testing.T) {
+		got, err := hoop_watcher.ResolveTeam(store, "20")
+		if err != nil || got.Abbreviation != "NYK" {
+			t.Errorf("got %v, %v, want the Knicks", got, err)
+		}
+		if _, err := hoop_watcher.ResolveTeam(store, "Supersonics"); !errors.Is(err, hoop_watcher.ErrTeamNotFound) {
+			t.Errorf("got err %v, want %v", err, hoop_watcher.ErrTeamNotFound)
+		}
+	})
+
+	t.Run("it sets favorites on the server", func(t *testing.T) {
+		if err := store.SetTeamFavorite(knicks.Id, true); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		got, err := store.GetFavoriteTeams()
+		if err != nil || len(got) != 1 || got[0].Id != knicks.Id {
+			t.Errorf("got %v, %v, want the Knicks", got, err)
+		}
+	})
+
+	t.Run("it lists every page of a team's games, most recent first", func(t *testing.T) {
+		got, err := store.GetGamesForTeam(knicks.Id)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != hoop_watcher.MaxGamesLimit+1 || !got[0].Date.Equal(openingNight.AddDate(0, 0, hoop_watcher.MaxGamesLimit)) {
+			t.Errorf("got %d games starting %v, want %d starting with the last", len(got), got[0].Date, hoop_watcher.MaxGamesLimit+1)
+		}
+	})
+
+	t.Run("it finds a day's games and opening night", func(t *testing.T) {
+		games, err := store.GetGamesByDate(openingNight)
+		if err != nil || len(games) != 1 || games[0].HomeTeam.Id != heat.Id {
+			t.Errorf("got %v, %v, want the Knicks at the Heat", games, err)
+		}
+		got, err := store.OpeningNight(2023)
+		if err != nil || !got.Equal(openingNight) {
+			t.Errorf("got %v, %v, want %v", got, err, openingNight)
+		}
+		if _, err := store.OpeningNight(2020); !errors.Is(err, client.ErrNotFound) {
+			t.Errorf("got err %v, want %v", err, client.ErrNotFound)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+// Errors an *APIError unwraps to, by the status the server responded with.
+var (
+	ErrBadRequest  = errors.New("Bad request")
+	ErrNotFound    = errors.New("Not found")
+	ErrServer      = errors.New("Server error")
+	ErrUnavailable = errors.New("Highlights unavailable")
+	// ErrQuotaExceeded is the server's highlight provider running out of
+	// quota, which retrying won't fix until it resets.
+	ErrQuotaExceeded = errors.New("Highlight search quota exceeded")
+)
+
+// APIError is a response of the server that was not a success.
+type APIError struct {
+	StatusCode int
+	// Message is the body of the response, or its error for a JSON body such
+	// as the server's 400s.
+	Message string
+	// Parameter is the missing or invalid parameter of a 400, if any.
+	Parameter string
+	// RetryAfter is how long the server asked to wait before trying again,
+	// or zero if it did not say.
+	RetryAfter time.Duration
+}
+
+// newAPIError reads the error out of a response's body and Retry-After header.
+func newAPIError(statusCode int, header http.Header, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Message: strings.TrimSpace(string(body))}
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	var parameterErr hoop_watcher.ParameterError
+	if json.Unmarshal(body, &parameterErr) == nil && parameterErr.Message != "" {
+		apiErr.Message = parameterErr.Message
+		apiErr.Parameter = parameterErr.Parameter
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Server responded %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Unwrap maps the status to the error handleDBError or a request's validation
+// responded with, so callers can use errors.Is.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case e.StatusCode == http.StatusServiceUnavailable:
+		return ErrUnavailable
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	}
+	return nil
+}
+
+// temporary reports whether the request may succeed if retried. A 429 is
+// left out since the server's quota won't reset within the retries.
+func (e *APIError) temporary() bool {
+	switch e.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
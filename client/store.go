@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+// Store keeps teams, favorites and games on the server, with the methods of
+// the local SQLite DB so a CLI can use either.
+func (c *Client) Store() *Store {
+	return &Store{client: c}
+}
+
+// Store looks up the server's teams and games through its /teams,
+// /teams/favorites and /games endpoints.
+type Store struct {
+	client *Client
+}
+
+// GetAllTeams returns every team the server knows.
+func (s *Store) GetAllTeams() ([]hoop_watcher.NBATeam, error) {
+	return s.client.ListTeams(context.Background())
+}
+
+// getTeam looks up the team the server resolves query to, wrapping
+// hoop_watcher.ErrTeamNotFound if there is none.
+func (s *Store) getTeam(query string) (hoop_watcher.NBATeam, error) {
+	team, err := s.client.GetTeam(context.Background(), query)
+	if errors.Is(err, ErrNotFound) {
+		return hoop_watcher.NBATeam{}, fmt.Errorf("%w: %v", hoop_watcher.ErrTeamNotFound, err)
+	}
+	return team, err
+}
+
+func (s *Store) GetTeamByID(id int) (hoop_watcher.NBATeam, error) {
+	return s.getTeam(strconv.Itoa(id))
+}
+
+func (s *Store) GetTeamByAbbrev(abbrev string) (hoop_watcher.NBATeam, error) {
+	return s.getTeam(abbrev)
+}
+
+func (s *Store) GetTeamByName(name string) (hoop_watcher.NBATeam, error) {
+	return s.getTeam(name)
+}
+
+// GetFavoriteTeams returns the teams marked as favorites on the server.
+func (s *Store) GetFavoriteTeams() ([]hoop_watcher.NBATeam, error) {
+	return s.client.ListFavoriteTeams(context.Background())
+}
+
+// SetTeamFavorite adds the team with teamId to the favorites, or removes it.
+func (s *Store) SetTeamFavorite(teamId int, favorite bool) error {
+	if favorite {
+		return s.client.FavoriteTeam(context.Background(), strconv.Itoa(teamId))
+	}
+	return s.client.UnfavoriteTeam(context.Background(), strconv.Itoa(teamId))
+}
+
+// listAllGames pages through the games list returns, earliest first.
+func listAllGames(list func(opts GameOptions) ([]hoop_watcher.GameWithHighlights, error), opts GameOptions) ([]hoop_watcher.Game, error) {
+	games := []hoop_watcher.Game{}
+	opts.Limit = hoop_watcher.MaxGamesLimit
+	for {
+		page, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, game := range page {
+			games = append(games, game.Game)
+		}
+		if len(page) < opts.Limit {
+			return games, nil
+		}
+		opts.Offset += len(page)
+	}
+}
+
+// GetGamesByDate returns the games on date.
+func (s *Store) GetGamesByDate(date time.Time) ([]hoop_watcher.Game, error) {
+	return listAllGames(func(opts GameOptions) ([]hoop_watcher.GameWithHighlights, error) {
+		return s.client.ListGames(context.Background(), opts)
+	}, GameOptions{Date: date})
+}
+
+// GetGamesForTeam returns the games of the team with teamId, most recent
+// first like the SQLite DB.
+func (s *Store) GetGamesForTeam(teamId int) ([]hoop_watcher.Game, error) {
+	games, err := listAllGames(func(opts GameOptions) ([]hoop_watcher.GameWithHighlights, error) {
+		return s.client.ListTeamGames(context.Background(), strconv.Itoa(teamId), opts)
+	}, GameOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(games)-1; i < j; i, j = i+1, j-1 {
+		games[i], games[j] = games[j], games[i]
+	}
+	return games, nil
+}
+
+// OpeningNight returns the date of the first game of season on the server.
+// Returns an error wrapping ErrNotFound if none of its games are known.
+func (s *Store) OpeningNight(season int) (time.Time, error) {
+	seasonStart := time.Date(season, time.August, 1, 0, 0, 0, 0, time.UTC)
+	games, err := s.client.ListGames(context.Background(), GameOptions{
+		From:  seasonStart,
+		To:    seasonStart.AddDate(1, 0, -1),
+		Limit: 1,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(games) == 0 {
+		return time.Time{}, fmt.Errorf("%w: no games in the %d season", ErrNotFound, season)
+	}
+	return games[0].Date, nil
+}
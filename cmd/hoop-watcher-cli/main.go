@@ -6,16 +6,18 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"net/url"
 	"os"
-	"os/exec"
 	"path"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
 
 	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+	"github.com/WesleyT4N/hoop-watcher-cli/client"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/joho/godotenv"
 	"google.golang.org/api/option"
@@ -25,13 +27,20 @@ import (
 var SupportedDateFormats = []string{
 	"2006-01-02",
 	"2006-01",
+	"2006/01/02",
+	"01/02/2006",
+	"1/2/2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"Jan 2 2006",
+	"January 2 2006",
 }
 
 var teamFilePath = path.Join(os.Getenv("HOME"), "bin", hoop_watcher.TeamFileName)
 
 func parseDate(dateStr string) (time.Time, error) {
 	if dateStr == "" {
-		return time.Now(), nil
+		return time.Time{}, nil
 	}
 	for _, format := range SupportedDateFormats {
 		gameDate, err := time.Parse(format, dateStr)
@@ -40,10 +49,86 @@ func parseDate(dateStr string) (time.Time, error) {
 		}
 		return gameDate, nil
 	}
+	if gameDate, err := hoop_watcher.ParseGameDate(dateStr, time.Now(), nil); err == nil {
+		return gameDate, nil
+	}
 	return time.Now(), fmt.Errorf("Invalid date")
 }
 
-func parseTeams(teamStr string, availableTeams []hoop_watcher.NBATeam) (teams []hoop_watcher.NBATeam, err error) {
+// chooseTeamFunc asks the user which of an ambiguous query's teams they meant.
+type chooseTeamFunc func(ambiguous *hoop_watcher.AmbiguousTeamError) (hoop_watcher.NBATeam, error)
+
+// resolveTeam finds the team query refers to by id, abbreviation, name or
+// alias, falling back to the best ranked team. Close calls go to choose, or
+// are returned as an *AmbiguousTeamError when choose is nil.
+func resolveTeam(query string, lookup hoop_watcher.TeamLookup, availableTeams []hoop_watcher.NBATeam, choose chooseTeamFunc) (hoop_watcher.NBATeam, error) {
+	team, err := hoop_watcher.ResolveTeam(lookup, query)
+	if !errors.Is(err, hoop_watcher.ErrTeamNotFound) {
+		return team, err
+	}
+	team, err = hoop_watcher.PickTeam(query, availableTeams)
+	var ambiguous *hoop_watcher.AmbiguousTeamError
+	if errors.As(err, &ambiguous) && choose != nil {
+		return choose(ambiguous)
+	}
+	return team, err
+}
+
+// promptTeamChoice asks on stdin which of the ambiguous teams was meant.
+func promptTeamChoice(ambiguous *hoop_watcher.AmbiguousTeamError) (hoop_watcher.NBATeam, error) {
+	fmt.Printf("Did you mean:\n")
+	for i, candidate := range ambiguous.Candidates {
+		fmt.Printf("[%d] %s\n", i+1, candidate.Team.FullName)
+	}
+	fmt.Print("> ")
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Scan()
+	num, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if scanner.Err() != nil || err != nil || num < 1 || num > len(ambiguous.Candidates) {
+		return hoop_watcher.NBATeam{}, ambiguous
+	}
+	return ambiguous.Candidates[num-1].Team, nil
+}
+
+// teamStore looks up teams and the season schedule.
+type teamStore interface {
+	hoop_watcher.TeamLookup
+	hoop_watcher.SeasonCalendar
+}
+
+// cliStore is where the CLI keeps teams, favorites and games: the local
+// SQLite DB, or a hoop-watcher-server's with -server.
+type cliStore interface {
+	teamStore
+	hoop_watcher.DigestStore
+	GetAllTeams() ([]hoop_watcher.NBATeam, error)
+	SetTeamFavorite(teamId int, favorite bool) error
+	GetGamesByDate(date time.Time) ([]hoop_watcher.Game, error)
+}
+
+// parseGameQuery parses a game described in plain words, such as "warriors at
+// lakers last night", resolving teams the same way as -tm.
+func parseGameQuery(query string, store teamStore, availableTeams []hoop_watcher.NBATeam, choose chooseTeamFunc, now time.Time) (hoop_watcher.GameQuery, error) {
+	parser := hoop_watcher.NewGameQueryParser(availableTeams, store)
+	parser.ResolveTeam = func(mention string) (hoop_watcher.NBATeam, error) {
+		return resolveTeam(mention, store, availableTeams, choose)
+	}
+	return parser.Parse(query, now)
+}
+
+// parseArgs parses the command line flags, allowing them to come after the
+// words of a game query, and returns the query.
+func parseArgs() string {
+	flag.Parse()
+	var words []string
+	for args := flag.Args(); len(args) > 0; args = flag.Args() {
+		words = append(words, args[0])
+		flag.CommandLine.Parse(args[1:])
+	}
+	return strings.Join(words, " ")
+}
+
+func parseTeams(teamStr string, lookup hoop_watcher.TeamLookup, availableTeams []hoop_watcher.NBATeam, choose chooseTeamFunc) (teams []hoop_watcher.NBATeam, err error) {
 	trimmedTeamStr := strings.TrimSpace(teamStr)
 	if trimmedTeamStr == "" {
 		return nil, nil
@@ -54,10 +139,11 @@ func parseTeams(teamStr string, availableTeams []hoop_watcher.NBATeam) (teams []
 	}
 
 	for _, team := range rawTeams {
-		parsedTeam := hoop_watcher.GetTeamFromQuery(team, availableTeams)
-		if parsedTeam != nil {
-			teams = append(teams, *parsedTeam)
+		parsedTeam, err := resolveTeam(team, lookup, availableTeams, choose)
+		if err != nil {
+			return nil, err
 		}
+		teams = append(teams, parsedTeam)
 	}
 	return teams, nil
 }
@@ -78,73 +164,419 @@ func parseFavoriteTeams(favoriteStr string) (favoriteTeams []int, err error) {
 	return favoriteTeams, nil
 }
 
-func parseFlags(availableTeams []hoop_watcher.NBATeam) (useTui bool, date time.Time, teams []hoop_watcher.NBATeam, err error) {
+type cliOptions struct {
+	useTui     bool
+	all        bool
+	date       time.Time
+	teams      []hoop_watcher.NBATeam
+	kind       hoop_watcher.HighlightKind
+	favorite   []int
+	unfavorite []int
+	pick       int
+	first      bool
+	printOnly  bool
+	output     hoop_watcher.OutputFormat
+	opener     hoop_watcher.Opener
+	// teamsArg and gameQuery are the -tm flag and the game described by the
+	// other arguments, which resolveGame turns into teams and date.
+	teamsArg  string
+	gameQuery string
+	// remote is the hoop-watcher-server to get teams, favorites, games and
+	// highlights from, nil to use the local DB and search YouTube directly.
+	remote *client.Client
+}
+
+// interactive reports whether the CLI may prompt on stdin.
+func (o cliOptions) interactive() bool {
+	return o.pick == 0 && !o.first && !o.printOnly && o.output == hoop_watcher.OutputText
+}
+
+// Exit codes of the CLI, so scripts can tell failures apart.
+const (
+	exitError        = 1
+	exitUsage        = 2
+	exitNoHighlights = 3
+	exitOpenFailed   = 4
+)
+
+// describeError adds a hint on what to do about the library's typed errors.
+func describeError(err error) string {
+	switch {
+	case errors.Is(err, hoop_watcher.ErrQuotaExceeded):
+		return err.Error() + "\nThe YouTube API quota resets daily, try again later or use another YOUTUBE_API_KEY"
+	case errors.Is(err, hoop_watcher.ErrProviderUnavailable):
+		return err.Error() + "\nCould not reach YouTube, check your connection and YOUTUBE_API_KEY"
+	case errors.Is(err, client.ErrQuotaExceeded):
+		return err.Error() + "\nThe hoop-watcher-server's YouTube API quota is used up, try again after it resets"
+	case errors.Is(err, client.ErrUnavailable):
+		return err.Error() + "\nThe hoop-watcher-server could not search highlights, try again later"
+	case errors.Is(err, client.ErrServer):
+		return err.Error() + "\nCheck the hoop-watcher-server's logs, or unset -server to search YouTube directly"
+	case errors.Is(err, hoop_watcher.ErrInvalidTeamsFile):
+		return err.Error() + "\nReinstall with install.sh to restore " + teamFilePath
+	case errors.Is(err, hoop_watcher.ErrUnknownDate):
+		return err.Error() + "\nTry a date like 2024-12-25, dec 25, yesterday, last friday or christmas"
+	case errors.Is(err, hoop_watcher.ErrAmbiguousTeam):
+		return err.Error() + "\nPass the team's full name or abbreviation instead"
+	case errors.Is(err, hoop_watcher.ErrTeamNotFound):
+		return err.Error() + "\nTry a team name, city or abbreviation, e.g. Knicks, New York or NYK"
+	}
+	return err.Error()
+}
+
+func exitWithError(code int, err error) {
+	fmt.Fprintln(os.Stderr, describeError(err))
+	os.Exit(code)
+}
+
+func parseFlags() (opts cliOptions, err error) {
 	tuiArg := flag.Bool("tui", false, "Use the TUI")
 	dateArg := flag.String("d", "", "Date of the highlights to fetch in the format YYYY-MM-DD")
 	teamsArg := flag.String("tm", "", "Which teams are playing (max 2) joined by ','")
-	flag.Parse()
+	kindArg := flag.String("kind", "", "Kind of highlights to fetch: recap, condensed or plays")
+	allArg := flag.Bool("all", false, "Fetch highlights for every game on the date (defaults to last night)")
+	favArg := flag.String("fav", "", "Team ids to add to your favorites joined by ','")
+	unfavArg := flag.String("unfav", "", "Team ids to remove from your favorites joined by ','")
+	pickArg := flag.Int("pick", 0, "Open the Nth highlight without prompting")
+	firstArg := flag.Bool("first", false, "Open the top ranked highlight without prompting")
+	printOnlyArg := flag.Bool("print-only", false, "List the highlights and exit without prompting")
+	outputArg := flag.String("output", "", "Print the highlights as json, csv, tsv or m3u and exit")
+	playerArg := flag.String("player", "", "Command to open highlights with, e.g. 'mpv {url}' (defaults to $"+hoop_watcher.PlayerEnv+" or your browser)")
+	serverArg := flag.String("server", "", "URL of a hoop-watcher-server to get teams, favorites, games and highlights from, e.g. http://localhost:8080 (defaults to $"+client.ServerEnv+")")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [game, e.g. \"warriors at lakers last night\"]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	opts.gameQuery = parseArgs()
+
+	opts.remote, err = newRemoteClient(*serverArg)
+	if err != nil {
+		return opts, err
+	}
+	opts.useTui = *tuiArg
+	opts.all = *allArg
+	opts.date, err = parseDate(*dateArg)
+	if err != nil {
+		return opts, err
+	}
+	opts.kind, err = hoop_watcher.ParseHighlightKind(*kindArg)
+	if err != nil {
+		return opts, err
+	}
+	opts.favorite, err = parseFavoriteTeams(*favArg)
+	if err != nil {
+		return opts, err
+	}
+	opts.unfavorite, err = parseFavoriteTeams(*unfavArg)
+	if err != nil {
+		return opts, err
+	}
+	if *pickArg < 0 {
+		return opts, errors.New("Invalid -pick, expected a highlight number")
+	}
+	opts.pick = *pickArg
+	opts.first = *firstArg
+	opts.printOnly = *printOnlyArg
+	opts.output, err = hoop_watcher.ParseOutputFormat(*outputArg)
+	if err != nil {
+		return opts, err
+	}
+	if opts.all && opts.output != hoop_watcher.OutputText {
+		return opts, errors.New("Invalid -output with -all, it only formats a single game's highlights")
+	}
+	opts.teamsArg = *teamsArg
+	opts.opener = hoop_watcher.OpenerFromEnv()
+	if *playerArg != "" {
+		opts.opener = hoop_watcher.NewPlayerOpener(runtime.GOOS, *playerArg)
+	}
+
+	return opts, nil
+}
+
+// resolveGame sets the teams and date of opts from its -tm flag and game
+// query, looking teams up in store.
+func (opts *cliOptions) resolveGame(store teamStore, availableTeams []hoop_watcher.NBATeam) (err error) {
+	var choose chooseTeamFunc
+	if opts.interactive() && !opts.useTui {
+		choose = promptTeamChoice
+	}
+	opts.teams, err = parseTeams(opts.teamsArg, store, availableTeams, choose)
+	if err != nil {
+		return err
+	}
+	if opts.gameQuery != "" {
+		parsed, err := parseGameQuery(opts.gameQuery, store, availableTeams, choose, time.Now())
+		if err != nil {
+			return err
+		}
+		if len(opts.teams) == 0 {
+			opts.teams = parsed.Teams
+		}
+		if opts.date.IsZero() {
+			opts.date = parsed.Date
+		}
+	}
+	return nil
+}
 
-	date, dateErr := parseDate(*dateArg)
-	if dateErr != nil {
-		return *tuiArg, date, teams, dateErr
+// newRemoteClient returns a client for the hoop-watcher-server at server, or
+// at $HOOP_WATCHER_SERVER when server is empty. Returns nil when neither is
+// set.
+func newRemoteClient(server string) (*client.Client, error) {
+	if server == "" {
+		server = os.Getenv(client.ServerEnv)
 	}
-	teams, teamErr := parseTeams(*teamsArg, availableTeams)
-	if teamErr != nil {
-		return *tuiArg, date, teams, teamErr
+	if server == "" {
+		return nil, nil
 	}
+	return client.New(server)
+}
 
-	return *tuiArg, date, teams, nil
+// openStore returns the store of remote and its highlight provider, which
+// shares the server's YouTube key and cache, or the local SQLite DB and
+// YouTube when remote is nil. The returned func closes the store.
+func openStore(remote *client.Client) (cliStore, hoop_watcher.HighlightProvider, func()) {
+	if remote != nil {
+		return remote.Store(), remote.HighlightProvider(), func() {}
+	}
+	db := openDB()
+	return db, newHighlightProvider(db), func() { db.Close() }
 }
 
-func runCLI() {
+func openDB() *hoop_watcher.SqliteHoopWatcherDB {
 	db, err := hoop_watcher.NewSqliteHoopWatcherDB("hoop-watcher-cli.db")
-	allTeams := hoop_watcher.GetNBATeamsFromDB(db)
-	allTeamsById := make(map[int]hoop_watcher.NBATeam)
-	for _, team := range allTeams {
-		allTeamsById[team.Id] = team
+	if err != nil {
+		exitWithError(exitError, fmt.Errorf("Error occurred setting up DB: %w", err))
+	}
+	if err := db.InitData(teamFilePath); err != nil {
+		exitWithError(exitError, err)
 	}
+	return db
+}
 
+// withScheduledOpponent adds the opponent of a single team from the games
+// table when it played on date.
+func withScheduledOpponent(store cliStore, teams []hoop_watcher.NBATeam, date time.Time) []hoop_watcher.NBATeam {
+	if len(teams) != 1 || date.IsZero() {
+		return teams
+	}
+	games, err := store.GetGamesByDate(date)
 	if err != nil {
-		log.Fatal("Error occurred setting up DB")
+		return teams
 	}
-	if db.InitData(teamFilePath) != nil {
-		log.Fatal("Error occurred initializing data in DB")
+	for _, game := range games {
+		if game.HomeTeam.Id == teams[0].Id || game.AwayTeam.Id == teams[0].Id {
+			return game.Teams()
+		}
 	}
-	useTui, date, teams, err := parseFlags(allTeams)
-	if useTui {
-		runTUI()
-		return
+	return teams
+}
+
+// lastNight is the date of yesterday's games.
+func lastNight() time.Time {
+	year, month, day := time.Now().AddDate(0, 0, -1).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func runAllGames(store cliStore, date time.Time, kind hoop_watcher.HighlightKind, provider hoop_watcher.HighlightProvider) error {
+	if date.IsZero() {
+		date = lastNight()
+	}
+	games, err := store.GetGamesByDate(date)
+	if err != nil {
+		return fmt.Errorf("could not load games: %v", err)
+	}
+	if len(games) == 0 {
+		return fmt.Errorf("No games found on %s, import a schedule with import-schedule first", date.Format(hoop_watcher.DAILY_DATE_FORMAT))
 	}
 
-	youtubeClient := newYoutubeClient()
-	stdout := os.Stdout
+	fmt.Printf("Getting highlights for %d games on %s\n\n", len(games), date.Format(hoop_watcher.HUMAN_DATE_FORMAT))
+	hoop_watcher.PrintGameHighlights(os.Stdout, hoop_watcher.GetHighlightsForGames(games, kind, provider))
+	return nil
+}
+
+func updateFavoriteTeams(store cliStore, favorite []int, unfavorite []int) error {
+	for _, teamId := range favorite {
+		if err := store.SetTeamFavorite(teamId, true); err != nil {
+			return fmt.Errorf("could not favorite team %d: %v", teamId, err)
+		}
+	}
+	for _, teamId := range unfavorite {
+		if err := store.SetTeamFavorite(teamId, false); err != nil {
+			return fmt.Errorf("could not unfavorite team %d: %v", teamId, err)
+		}
+	}
+
+	favoriteTeams, err := store.GetFavoriteTeams()
+	if err != nil {
+		return err
+	}
+	fmt.Println("Your favorite teams:")
+	for _, team := range favoriteTeams {
+		fmt.Printf("[%d] %s\n", team.Id, team.FullName)
+	}
+	return nil
+}
+
+func runDigest(args []string) {
+	digestFlags := flag.NewFlagSet("digest", flag.ExitOnError)
+	outputArg := digestFlags.String("o", "", "File to write the digest to instead of stdout")
+	formatArg := digestFlags.String("format", "", "Digest format: text, markdown or html (defaults to the output file's extension)")
+	kindArg := digestFlags.String("kind", "", "Kind of highlights to fetch: recap, condensed or plays")
+	serverArg := digestFlags.String("server", "", "URL of a hoop-watcher-server to get favorites, games and highlights from (defaults to $"+client.ServerEnv+")")
+	digestFlags.Parse(args)
 
+	format, err := hoop_watcher.ParseDigestFormat(*formatArg, *outputArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	kind, err := hoop_watcher.ParseHighlightKind(*kindArg)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	remote, err := newRemoteClient(*serverArg)
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
 
-	if len(teams) == 0 {
-		teams, err = scanTeam(allTeams)
+	store, provider, closeStore := openStore(remote)
+	defer closeStore()
+	now := time.Now()
+	entries, err := hoop_watcher.BuildDigest(store, provider, kind, now)
+	if err != nil {
+		fmt.Printf("Error occurred building digest: %v\n", err)
+		os.Exit(1)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No favorite teams, add some with --fav")
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if *outputArg != "" {
+		out, err = os.Create(*outputArg)
 		if err != nil {
-			fmt.Println(err.Error())
+			fmt.Printf("Error occurred creating %s: %v\n", *outputArg, err)
 			os.Exit(1)
 		}
+		defer out.Close()
+	}
+	if err := hoop_watcher.WriteDigest(out, entries, format, now); err != nil {
+		fmt.Printf("Error occurred writing digest: %v\n", err)
+		os.Exit(1)
 	}
+}
 
-	highlights := hoop_watcher.GetHighlights(teams, stdout, youtubeClient, date)
-	err = openHighlight(highlights)
+func runImportSchedule(args []string) {
+	if len(args) != 1 {
+		fmt.Println("Usage: hoop-watcher-cli import-schedule <file.json|file.csv>")
+		os.Exit(1)
+	}
+	db := openDB()
+	defer db.Close()
+
+	imported, err := hoop_watcher.ImportScheduleFile(db, args[0])
 	if err != nil {
-		fmt.Println(err.Error())
+		fmt.Printf("Imported %d games before an error occurred: %v\n", imported, err)
 		os.Exit(1)
 	}
+	fmt.Printf("Imported %d games from %s\n", imported, args[0])
 }
 
-func runTUI() {
-	err := godotenv.Load(path.Join(os.Getenv("HOME"), ".env"))
+func runCLI() {
+	opts, err := parseFlags()
+	if err != nil && !opts.useTui {
+		exitWithError(exitUsage, err)
+	}
+	store, highlightProvider, closeStore := openStore(opts.remote)
+	defer closeStore()
+	allTeams, err := store.GetAllTeams()
 	if err != nil {
-		log.Fatal("Error occurred loading .env file")
+		exitWithError(exitError, fmt.Errorf("could not load NBA teams: %w", err))
+	}
+	if opts.useTui {
+		runTUI(opts.kind, opts.opener, store, allTeams, highlightProvider)
+		return
+	}
+	if err := opts.resolveGame(store, allTeams); err != nil {
+		exitWithError(exitUsage, err)
+	}
+
+	stdout := os.Stdout
+
+	if len(opts.favorite) > 0 || len(opts.unfavorite) > 0 {
+		if err := updateFavoriteTeams(store, opts.favorite, opts.unfavorite); err != nil {
+			exitWithError(exitError, err)
+		}
+		return
+	}
+
+	if opts.all {
+		if err := runAllGames(store, opts.date, opts.kind, highlightProvider); err != nil {
+			exitWithError(exitError, err)
+		}
+		return
+	}
+
+	teams := opts.teams
+	if len(teams) == 0 {
+		if !opts.interactive() {
+			exitWithError(exitUsage, errors.New("Missing team, pass one or two with -tm"))
+		}
+		teams, err = scanTeam(store, allTeams)
+		if err != nil {
+			exitWithError(exitUsage, err)
+		}
+	}
+
+	teams = withScheduledOpponent(store, teams, opts.date)
+
+	query := hoop_watcher.HighlightQuery{Teams: teams, Date: opts.date, Kind: opts.kind}
+	if opts.output != hoop_watcher.OutputText {
+		highlights, err := hoop_watcher.FindHighlights(query, highlightProvider)
+		if err != nil {
+			exitWithError(exitError, err)
+		}
+		if err := hoop_watcher.WriteHighlights(stdout, highlights, opts.output); err != nil {
+			exitWithError(exitError, err)
+		}
+		return
+	}
+	highlights, err := hoop_watcher.GetHighlights(query, stdout, highlightProvider)
+	if err != nil {
+		exitWithError(exitError, err)
 	}
+	if opts.printOnly {
+		return
+	}
+	if len(highlights) == 0 {
+		exitWithError(exitNoHighlights, errors.New("No highlights found"))
+	}
+
+	num := opts.pick
+	if opts.first {
+		num = 1
+	}
+	if num == 0 {
+		num, err = scanHighlightNumber()
+		if err != nil {
+			exitWithError(exitUsage, err)
+		}
+	}
+	highlight, err := pickHighlight(highlights, num)
+	if err != nil {
+		exitWithError(exitUsage, err)
+	}
+	fmt.Println("Opening the highlight...")
+	if err := opts.opener.Open(highlight); err != nil {
+		exitWithError(exitOpenFailed, err)
+	}
+}
+
+func runTUI(kind hoop_watcher.HighlightKind, opener hoop_watcher.Opener, store cliStore, allTeams []hoop_watcher.NBATeam, provider hoop_watcher.HighlightProvider) {
 	if os.Getenv("DEBUG") == "1" {
 		f, err := tea.LogToFile("debug.log", "[DEBUG]")
 		if err != nil {
@@ -153,39 +585,61 @@ func runTUI() {
 		}
 		defer f.Close()
 	}
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
+	m := initialModel(kind, opener, store, allTeams, provider)
+	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		os.Exit(1)
 	}
 }
 
+// loadEnv loads ~/.env into the environment. The file is optional, e.g. when
+// highlights come from a hoop-watcher-server.
+func loadEnv() {
+	err := godotenv.Load(path.Join(os.Getenv("HOME"), ".env"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatal("Error occurred loading .env file")
+	}
+}
+
 func main() {
+	loadEnv()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import-schedule":
+			runImportSchedule(os.Args[2:])
+			return
+		case "digest":
+			runDigest(os.Args[2:])
+			return
+		}
+	}
 	runCLI()
 }
 
-func openHighlight(highlights []url.URL) error {
+func scanHighlightNumber() (int, error) {
 	fmt.Print("Which one do you want to view? (enter the corresponding number)\n> ")
 	scanner := bufio.NewScanner(os.Stdin)
 	scanner.Scan()
 	if scanner.Err() != nil {
-		return errors.New("Error occurred parsing num")
+		return 0, errors.New("Error occurred parsing num")
 	}
 	num, err := strconv.Atoi(scanner.Text())
-	if err != nil || !(0 <= num-1 && num-1 <= len(highlights)) {
-		return errors.New("Error occurred parsing num")
+	if err != nil {
+		return 0, errors.New("Error occurred parsing num")
 	}
+	return num, nil
+}
 
-	fmt.Println("Opening the highlight in your browser...")
-	cmd := exec.Command("open", highlights[num-1].String())
-	err = cmd.Run()
-	if err != nil {
-		return fmt.Errorf("could not open highlight: %v", err)
+// pickHighlight returns the highlight numbered num in the printed list.
+func pickHighlight(highlights []url.URL, num int) (url.URL, error) {
+	if num < 1 || num > len(highlights) {
+		return url.URL{}, fmt.Errorf("Invalid highlight number %d, expected 1 to %d", num, len(highlights))
 	}
-	return nil
+	return highlights[num-1], nil
 }
 
-func scanTeam(allTeams []hoop_watcher.NBATeam) ([]hoop_watcher.NBATeam, error) {
+func scanTeam(lookup hoop_watcher.TeamLookup, allTeams []hoop_watcher.NBATeam) ([]hoop_watcher.NBATeam, error) {
 	scanner := bufio.NewScanner(os.Stdin)
 
 	fmt.Print("Enter the NBA team you want to get highlights for:\n> ")
@@ -194,27 +648,37 @@ func scanTeam(allTeams []hoop_watcher.NBATeam) ([]hoop_watcher.NBATeam, error) {
 		return nil, errors.New("Error occurred parsing team")
 	}
 
-	team := scanner.Text()
-
-	parsedTeam := hoop_watcher.FuzzyGetTeamFromQuery(team, allTeams)
-	if parsedTeam == nil {
-		return nil, errors.New("Unknown team")
-	}
-	return []hoop_watcher.NBATeam{*parsedTeam}, nil
-}
-
-func newYoutubeClient() *youtube.Service {
-	err := godotenv.Load(path.Join(os.Getenv("HOME"), ".env"))
+	team, err := resolveTeam(scanner.Text(), lookup, allTeams, promptTeamChoice)
 	if err != nil {
-		log.Fatal("Error occurred loading .env file")
+		return nil, err
 	}
+	return []hoop_watcher.NBATeam{team}, nil
+}
 
+// newHighlightProvider searches YouTube directly, caching the results in
+// cache.
+func newHighlightProvider(cache hoop_watcher.HighlightCache) hoop_watcher.HighlightProvider {
 	youtubeApiKey := os.Getenv("YOUTUBE_API_KEY")
+	if youtubeApiKey == "" {
+		exitWithError(exitUsage, errors.New("Missing YOUTUBE_API_KEY, add it to ~/.env or pass -server"))
+	}
 	ctx := context.Background()
 	youtubeClient, err := youtube.NewService(ctx, option.WithAPIKey(youtubeApiKey))
 	if err != nil {
 		log.Fatal("Error occurred setting up Youtube Client")
 	}
 
-	return youtubeClient
+	cacheTTL, err := hoop_watcher.CacheTTLFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return hoop_watcher.NewCachedHighlightProvider(
+		hoop_watcher.NewChannelFilteredHighlightProvider(
+			hoop_watcher.NewYoutubeHighlightProvider(youtubeClient),
+			hoop_watcher.ChannelFilterFromEnv(),
+		),
+		cache,
+		cacheTTL,
+	)
 }
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
 
 	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+	"github.com/WesleyT4N/hoop-watcher-cli/client"
+	"github.com/charmbracelet/bubbles/list"
 )
 
 func TestParseDate(t *testing.T) {
@@ -31,6 +38,25 @@ func TestParseDate(t *testing.T) {
 		}
 	})
 
+	t.Run("it parses other layouts and date phrases", func(t *testing.T) {
+		got, err := parseDate("Jan 5, 2024")
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if want := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC); got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+
+		got, err = parseDate("yesterday")
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		year, month, day := time.Now().AddDate(0, 0, -1).Date()
+		if want := time.Date(year, month, day, 0, 0, 0, 0, time.UTC); got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+
 	t.Run("raises error if invalid date", func(t *testing.T) {
 		_, err := parseDate("2020-13")
 		if err == nil {
@@ -44,46 +70,48 @@ func TestParseDate(t *testing.T) {
 	})
 }
 
+func newTestDB(t *testing.T) *hoop_watcher.SqliteHoopWatcherDB {
+	t.Helper()
+	db, err := hoop_watcher.NewSqliteHoopWatcherDB(filepath.Join(t.TempDir(), "hoop-watcher-test.db"))
+	if err != nil {
+		t.Fatalf("could not create test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.InitData(teamFilePath); err != nil {
+		t.Fatalf("could not init test db: %v", err)
+	}
+	return db
+}
+
 func TestParseTeams(t *testing.T) {
-	availableTeams := hoop_watcher.GetNBATeamsFromJSON(teamFilePath)
+	db := newTestDB(t)
+	availableTeams, err := hoop_watcher.GetNBATeamsFromJSON(teamFilePath)
+	if err != nil {
+		t.Fatalf("could not load teams: %v", err)
+	}
 
 	t.Run("parses two team string into NBATeam", func(t *testing.T) {
-		got, err := parseTeams("knicks,grizzlies", availableTeams)
+		got, err := parseTeams("knicks,grizzlies", db, availableTeams, nil)
 		if err != nil {
 			t.Fatalf("Found err: %v", err)
 		}
 
-		want := []hoop_watcher.NBATeam{
-			{
-				Name:         "New York Knicks",
-				Abbreviation: "NYK",
-			}, {
-				Name:         "Memphis Grizzlies",
-				Abbreviation: "MEM",
-			},
-		}
-		if !reflect.DeepEqual(got, want) {
-			t.Fatalf("got %v, want %v", got, want)
+		if len(got) != 2 || got[0].Abbreviation != "NYK" || got[1].Abbreviation != "MEM" {
+			t.Fatalf("got %v, want the Knicks and the Grizzlies", got)
 		}
 	})
 	t.Run("parses singular team string into NBATeam", func(t *testing.T) {
-		got, err := parseTeams("knicks", availableTeams)
+		got, err := parseTeams("knicks", db, availableTeams, nil)
 		if err != nil {
 			t.Fatalf("Found err: %v", err)
 		}
 
-		want := []hoop_watcher.NBATeam{
-			{
-				Name:         "New York Knicks",
-				Abbreviation: "NYK",
-			},
-		}
-		if !reflect.DeepEqual(got, want) {
-			t.Fatalf("got %v, want %v", got, want)
+		if len(got) != 1 || got[0].Abbreviation != "NYK" {
+			t.Fatalf("got %v, want the Knicks", got)
 		}
 	})
 	t.Run("returns error on more than 2 teams", func(t *testing.T) {
-		_, err := parseTeams("knicks,knicks,knicks", availableTeams)
+		_, err := parseTeams("knicks,knicks,knicks", db, availableTeams, nil)
 		if err == nil {
 			t.Fatal("Expected error but found none")
 		}
@@ -93,8 +121,48 @@ func TestParseTeams(t *testing.T) {
 			t.Fatalf("got %s, want %s", got, want)
 		}
 	})
+	t.Run("parses aliases and loose names", func(t *testing.T) {
+		got, err := parseTeams("sixers, ny knicks", db, availableTeams, nil)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 2 || got[0].Abbreviation != "PHI" || got[1].Abbreviation != "NYK" {
+			t.Fatalf("got %v, want the 76ers and the Knicks", got)
+		}
+	})
+	t.Run("returns an ambiguous team error for close calls", func(t *testing.T) {
+		_, err := parseTeams("LA", db, availableTeams, nil)
+		if !errors.Is(err, hoop_watcher.ErrAmbiguousTeam) {
+			t.Fatalf("got err %v, want %v", err, hoop_watcher.ErrAmbiguousTeam)
+		}
+	})
+	t.Run("asks which team was meant for close calls", func(t *testing.T) {
+		var gotCandidates []string
+		choose := func(ambiguous *hoop_watcher.AmbiguousTeamError) (hoop_watcher.NBATeam, error) {
+			for _, candidate := range ambiguous.Candidates {
+				gotCandidates = append(gotCandidates, candidate.Team.Abbreviation)
+			}
+			return ambiguous.Candidates[1].Team, nil
+		}
+		got, err := parseTeams("LA", db, availableTeams, choose)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if !reflect.DeepEqual(gotCandidates, []string{"LAC", "LAL"}) {
+			t.Errorf("got candidates %v, want LAC and LAL", gotCandidates)
+		}
+		if len(got) != 1 || got[0].Abbreviation != "LAL" {
+			t.Errorf("got %v, want the Lakers", got)
+		}
+	})
+	t.Run("returns ErrTeamNotFound for an unknown team", func(t *testing.T) {
+		_, err := parseTeams("knicks,supersonics", db, availableTeams, nil)
+		if !errors.Is(err, hoop_watcher.ErrTeamNotFound) {
+			t.Fatalf("got err %v, want %v", err, hoop_watcher.ErrTeamNotFound)
+		}
+	})
 	t.Run("returns nil on 0 teams", func(t *testing.T) {
-		got, err := parseTeams("", availableTeams)
+		got, err := parseTeams("", db, availableTeams, nil)
 		if err != nil {
 			t.Fatalf("Found err: %v", err)
 		}
@@ -103,3 +171,217 @@ func TestParseTeams(t *testing.T) {
 		}
 	})
 }
+
+func TestParseGameQuery(t *testing.T) {
+	db := newTestDB(t)
+	availableTeams, err := hoop_watcher.GetNBATeamsFromJSON(teamFilePath)
+	if err != nil {
+		t.Fatalf("could not load teams: %v", err)
+	}
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseGameQuery("dubs @ sixers last night", db, availableTeams, nil, now)
+	if err != nil {
+		t.Fatalf("Found err: %v", err)
+	}
+	if len(got.Teams) != 2 || got.Teams[0].Abbreviation != "GSW" || got.Teams[1].Abbreviation != "PHI" {
+		t.Errorf("got %+v, want the Warriors at the 76ers", got)
+	}
+	if want := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC); !got.Date.Equal(want) {
+		t.Errorf("got date %v, want %v", got.Date, want)
+	}
+
+	if _, err := parseGameQuery("knicks on opening night", db, availableTeams, nil, now); !errors.Is(err, hoop_watcher.ErrUnknownDate) {
+		t.Errorf("got err %v, want %v before the schedule is imported", err, hoop_watcher.ErrUnknownDate)
+	}
+}
+
+func TestParseFavoriteTeams(t *testing.T) {
+	t.Run("parses team ids joined by ','", func(t *testing.T) {
+		got, err := parseFavoriteTeams("20,2")
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		want := []int{20, 2}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+	t.Run("returns error on invalid team id", func(t *testing.T) {
+		_, err := parseFavoriteTeams("knicks")
+		if err == nil {
+			t.Fatal("Expected error but found none")
+		}
+	})
+}
+
+func TestTeamItems(t *testing.T) {
+	knicks := hoop_watcher.NBATeam{Id: 20, Abbreviation: "NYK"}
+	celtics := hoop_watcher.NBATeam{Id: 2, Abbreviation: "BOS"}
+	hawks := hoop_watcher.NBATeam{Id: 1, Abbreviation: "ATL"}
+
+	got := teamItems([]hoop_watcher.NBATeam{hawks, celtics, knicks}, []hoop_watcher.NBATeam{knicks})
+	want := []list.Item{
+		Team{team: knicks, favorite: true},
+		Team{team: hawks},
+		Team{team: celtics},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestPickHighlight(t *testing.T) {
+	highlights := []url.URL{
+		{Scheme: "https", Host: "www.youtube.com", Path: "/watch", RawQuery: "v=first"},
+		{Scheme: "https", Host: "www.youtube.com", Path: "/watch", RawQuery: "v=second"},
+	}
+
+	t.Run("picks the highlight by its number in the list", func(t *testing.T) {
+		got, err := pickHighlight(highlights, 2)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if got != highlights[1] {
+			t.Fatalf("got %v, want %v", got, highlights[1])
+		}
+	})
+	t.Run("returns error on a number outside the list", func(t *testing.T) {
+		for _, num := range []int{0, 3} {
+			if _, err := pickHighlight(highlights, num); err == nil {
+				t.Fatalf("Expected error for %d but found none", num)
+			}
+		}
+	})
+}
+
+func TestCLIOptionsInteractive(t *testing.T) {
+	cases := map[string]struct {
+		opts cliOptions
+		want bool
+	}{
+		"no flags":    {cliOptions{output: hoop_watcher.OutputText}, true},
+		"-pick":       {cliOptions{pick: 2, output: hoop_watcher.OutputText}, false},
+		"-first":      {cliOptions{first: true, output: hoop_watcher.OutputText}, false},
+		"-print-only": {cliOptions{printOnly: true, output: hoop_watcher.OutputText}, false},
+		"-output":     {cliOptions{output: hoop_watcher.OutputJSON}, false},
+	}
+	for name, c := range cases {
+		if got := c.opts.interactive(); got != c.want {
+			t.Errorf("got %v for %s, want %v", got, name, c.want)
+		}
+	}
+}
+
+type fakeHighlightProvider struct {
+	highlights []hoop_watcher.Highlight
+	gotQuery   hoop_watcher.HighlightQuery
+}
+
+func (f *fakeHighlightProvider) SearchHighlights(query hoop_watcher.HighlightQuery) ([]hoop_watcher.Highlight, error) {
+	f.gotQuery = query
+	return f.highlights, nil
+}
+
+func TestRemoteMode(t *testing.T) {
+	serverDB := newTestDB(t)
+	highlight := hoop_watcher.Highlight{
+		Title:   "Knicks vs Heat Full Game Highlights",
+		URL:     url.URL{Scheme: "https", Host: "www.youtube.com", Path: "/watch", RawQuery: "v=abc"},
+		Channel: "NBA",
+		Trusted: true,
+	}
+	serverProvider := &fakeHighlightProvider{highlights: []hoop_watcher.Highlight{highlight}}
+	h := hoop_watcher.NewBaseHandler(serverDB, serverProvider)
+	router := http.NewServeMux()
+	h.RegisterRoutes(router)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	t.Setenv(client.ServerEnv, server.URL)
+	remote, err := newRemoteClient("")
+	if err != nil || remote == nil {
+		t.Fatalf("got %v, %v, want a client for $%s", remote, err, client.ServerEnv)
+	}
+
+	store, provider, closeStore := openStore(remote)
+	defer closeStore()
+
+	t.Run("it lists the server's teams", func(t *testing.T) {
+		got, err := store.GetAllTeams()
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 30 {
+			t.Errorf("got %d teams, want 30", len(got))
+		}
+	})
+
+	t.Run("it gets highlights from the server's provider", func(t *testing.T) {
+		knicks, err := serverDB.GetTeamByAbbrev("NYK")
+		if err != nil {
+			t.Fatalf("could not load team: %v", err)
+		}
+		heat, err := serverDB.GetTeamByAbbrev("MIA")
+		if err != nil {
+			t.Fatalf("could not load team: %v", err)
+		}
+		date := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		query := hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks, heat}, Date: date}
+
+		got, err := hoop_watcher.FindHighlights(query, provider)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 1 || got[0].URL != highlight.URL {
+			t.Errorf("got %v, want %v", got, highlight)
+		}
+		if !reflect.DeepEqual(serverProvider.gotQuery.Teams, query.Teams) || serverProvider.gotQuery.Date != date {
+			t.Errorf("server searched %v, want %v", serverProvider.gotQuery, query)
+		}
+	})
+
+	t.Run("it leaves the most recent game to the server without a date", func(t *testing.T) {
+		knicks, err := serverDB.GetTeamByAbbrev("NYK")
+		if err != nil {
+			t.Fatalf("could not load team: %v", err)
+		}
+		query := hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}}
+
+		if _, err := hoop_watcher.FindHighlights(query, provider); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if !serverProvider.gotQuery.Date.IsZero() {
+			t.Errorf("server searched %v, want no date", serverProvider.gotQuery.Date)
+		}
+	})
+
+	t.Run("it keeps favorites and games on the server", func(t *testing.T) {
+		date := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		knicks, _ := serverDB.GetTeamByAbbrev("NYK")
+		heat, _ := serverDB.GetTeamByAbbrev("MIA")
+		if _, err := serverDB.AddGame(hoop_watcher.Game{HomeTeam: heat, AwayTeam: knicks, Date: date}); err != nil {
+			t.Fatalf("could not add game: %v", err)
+		}
+		if err := store.SetTeamFavorite(knicks.Id, true); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if favorites, err := serverDB.GetFavoriteTeams(); err != nil || len(favorites) != 1 || favorites[0].Id != knicks.Id {
+			t.Errorf("got server favorites %v, %v, want the Knicks", favorites, err)
+		}
+
+		entries, err := hoop_watcher.BuildDigest(store, provider, hoop_watcher.AnyHighlights, date.AddDate(0, 0, 1))
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(entries) != 1 || entries[0].Game == nil || !entries[0].Game.Date.Equal(date) || entries[0].Highlight == nil {
+			t.Errorf("got %v, want the highlight of the Knicks' game on %v", entries, date)
+		}
+	})
+
+	t.Run("it rejects an invalid server URL", func(t *testing.T) {
+		if _, err := newRemoteClient("localhost:8080"); err == nil {
+			t.Error("got no err, want an invalid server URL error")
+		}
+	})
+}
@@ -1,60 +1,110 @@
 package main
 
 import (
+	"fmt"
 	"log"
-	"os"
-	"os/exec"
-	"time"
+	"net/url"
+	"strings"
 
 	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"google.golang.org/api/youtube/v3"
 )
 
 var (
-	docStyle  = lipgloss.NewStyle().Margin(1, 2)
-	tableSyle = table.DefaultStyles()
+	docStyle    = lipgloss.NewStyle().Margin(1, 2)
+	statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	tableSyle   = table.DefaultStyles()
 )
 
 type model struct {
 	list            list.Model
 	table           table.Model
 	hasSelectedTeam bool
-	highlights      map[Team][]hoop_watcher.Highlight
-	yt              *youtube.Service
+	highlights      map[list.Item][]hoop_watcher.Highlight
+	store           cliStore
+	provider        hoop_watcher.HighlightProvider
+	kind            hoop_watcher.HighlightKind
+	opener          hoop_watcher.Opener
+	status          string
+}
+
+var toggleKindKey = key.NewBinding(
+	key.WithKeys("tab"),
+	key.WithHelp("tab", "toggle highlight kind"),
+)
+
+func listTitle(kind hoop_watcher.HighlightKind) string {
+	return fmt.Sprintf("Hoop Watcher CLI (%s highlights)", kind)
 }
 
 type Team struct {
-	team hoop_watcher.NBATeam
+	team     hoop_watcher.NBATeam
+	favorite bool
 }
 
 func (i Team) FilterValue() string { return i.team.Name + i.team.Abbreviation }
-func (i Team) Title() string       { return i.team.Abbreviation }
 func (i Team) Description() string { return i.team.Name }
+func (i Team) Title() string {
+	if i.favorite {
+		return "★ " + i.team.Abbreviation
+	}
+	return i.team.Abbreviation
+}
+
+// AllGames is the list entry for the highlights of every game last night.
+type AllGames struct{}
 
-func initList() list.Model {
-	allTeams := hoop_watcher.GetNBATeamsFromJSON(teamFilePath)
+func (i AllGames) FilterValue() string { return "all" }
+func (i AllGames) Title() string       { return "ALL" }
+func (i AllGames) Description() string { return "Every game last night" }
+
+// teamItems lists the favorite teams first, then the rest.
+func teamItems(allTeams []hoop_watcher.NBATeam, favoriteTeams []hoop_watcher.NBATeam) []list.Item {
+	isFavorite := map[int]bool{}
 	var items []list.Item
+	for _, team := range favoriteTeams {
+		isFavorite[team.Id] = true
+		items = append(items, Team{team: team, favorite: true})
+	}
 	for _, team := range allTeams {
-		items = append(items, Team{
-			team: team,
-		})
+		if !isFavorite[team.Id] {
+			items = append(items, Team{team: team})
+		}
+	}
+	return items
+}
+
+func initList(kind hoop_watcher.HighlightKind, allTeams []hoop_watcher.NBATeam, store cliStore) list.Model {
+	favoriteTeams, err := store.GetFavoriteTeams()
+	if err != nil {
+		log.Printf("Error occurred loading favorite teams: %v", err)
 	}
+	items := append([]list.Item{AllGames{}}, teamItems(allTeams, favoriteTeams)...)
 
 	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
-	l.Title = "Hoop Watcher CLI"
+	l.Title = listTitle(kind)
+	l.AdditionalShortHelpKeys = func() []key.Binding { return []key.Binding{toggleKindKey} }
 	l.SetShowStatusBar(true)
 	l.DisableQuitKeybindings()
 	return l
 }
 
+// urlColumn is the index of the URL column in the highlights table.
+const urlColumn = 6
+
 func initTable() table.Model {
 	columns := []table.Column{
-		{Title: "Video", Width: 100},
-		{Title: "URL", Width: 100},
+		{Title: "Video", Width: 60},
+		{Title: "Channel", Width: 20},
+		{Title: "Length", Width: 8},
+		{Title: "Views", Width: 8},
+		{Title: "Published", Width: 10},
+		{Title: "Quality", Width: 7},
+		{Title: "URL", Width: 45},
 	}
 
 	t := table.New(
@@ -65,13 +115,16 @@ func initTable() table.Model {
 	return t
 }
 
-func initialModel() model {
+func initialModel(kind hoop_watcher.HighlightKind, opener hoop_watcher.Opener, store cliStore, allTeams []hoop_watcher.NBATeam, provider hoop_watcher.HighlightProvider) model {
 	return model{
-		list:            initList(),
+		list:            initList(kind, allTeams, store),
 		table:           initTable(),
 		hasSelectedTeam: false,
-		highlights:      map[Team][]hoop_watcher.Highlight{},
-		yt:              newYoutubeClient(),
+		highlights:      map[list.Item][]hoop_watcher.Highlight{},
+		store:           store,
+		provider:        provider,
+		kind:            kind,
+		opener:          opener,
 	}
 }
 
@@ -81,16 +134,60 @@ func (m model) Init() tea.Cmd {
 
 type highlightLookupMsg struct {
 	highlights []hoop_watcher.Highlight
+	err        error
+}
+
+func lookupHighlight(team hoop_watcher.NBATeam, kind hoop_watcher.HighlightKind, provider hoop_watcher.HighlightProvider) tea.Cmd {
+	return func() tea.Msg {
+		query := hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{team}, Kind: kind}
+		highlights, err := hoop_watcher.GetHighlightsForTUI(query, provider)
+		return highlightLookupMsg{highlights: highlights, err: err}
+	}
 }
 
-func lookupHighlight(team hoop_watcher.NBATeam, yt *youtube.Service) tea.Cmd {
+// lookupAllGames fetches the highlights of every game last night, labelling
+// each with its matchup.
+func lookupAllGames(store cliStore, kind hoop_watcher.HighlightKind, provider hoop_watcher.HighlightProvider) tea.Cmd {
 	return func() tea.Msg {
-		return highlightLookupMsg{
-			highlights: hoop_watcher.GetHighlightsForTUI(team, time.Now(), yt),
+		highlights := []hoop_watcher.Highlight{}
+		games, err := store.GetGamesByDate(lastNight())
+		if err != nil {
+			log.Printf("Error occurred loading games: %v", err)
+			return highlightLookupMsg{highlights: highlights, err: err}
 		}
+		var lookupErr error
+		for _, result := range hoop_watcher.GetHighlightsForGames(games, kind, provider) {
+			if result.Err != nil {
+				lookupErr = result.Err
+			}
+			for _, h := range result.Highlights {
+				h.Title = fmt.Sprintf("%s | %s", result.Game, h.Title)
+				highlights = append(highlights, h)
+			}
+		}
+		return highlightLookupMsg{highlights: highlights, err: lookupErr}
 	}
 }
 
+type openResultMsg struct {
+	err error
+}
+
+// openHighlight hands the terminal to the opener, so players like mpv can use
+// it, and reports back whether it succeeded.
+func openHighlight(opener hoop_watcher.Opener, rawURL string) tea.Cmd {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return func() tea.Msg { return openResultMsg{err: err} }
+	}
+	return tea.ExecProcess(opener.Command(*u), func(err error) tea.Msg {
+		if err != nil {
+			err = fmt.Errorf("could not open highlight: %w", err)
+		}
+		return openResultMsg{err: err}
+	})
+}
+
 func (m model) Update(msg tea.Msg) (n tea.Model, cmd tea.Cmd) {
 	log.Printf("Msg: %T, %v\n", msg, msg)
 	log.Printf("Selected Team: %v\n", m.list.SelectedItem())
@@ -100,17 +197,26 @@ func (m model) Update(msg tea.Msg) (n tea.Model, cmd tea.Cmd) {
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
+		case "tab":
+			if !m.hasSelectedTeam && !m.list.SettingFilter() {
+				m.kind = m.kind.Next()
+				m.highlights = map[list.Item][]hoop_watcher.Highlight{}
+				m.list.Title = listTitle(m.kind)
+				return m, nil
+			}
 		case "enter":
 			selectedItem := m.list.SelectedItem()
 			if selectedItem != nil && !m.hasSelectedTeam && !m.list.SettingFilter() {
-				selectedTeam := selectedItem.(Team)
 				m.hasSelectedTeam = true
-				return m, lookupHighlight(selectedTeam.team, m.yt)
-			} else if m.table.Focused() {
-				cmd := exec.Command("open", m.table.SelectedRow()[1])
-				if cmd.Run() != nil {
-					os.Exit(1)
+				switch item := selectedItem.(type) {
+				case AllGames:
+					return m, lookupAllGames(m.store, m.kind, m.provider)
+				case Team:
+					return m, lookupHighlight(item.team, m.kind, m.provider)
 				}
+			} else if m.table.Focused() {
+				m.status = ""
+				return m, openHighlight(m.opener, m.table.SelectedRow()[urlColumn])
 			}
 		case "esc":
 			m.list.ResetFilter()
@@ -122,13 +228,33 @@ func (m model) Update(msg tea.Msg) (n tea.Model, cmd tea.Cmd) {
 				m.table.Blur()
 			}
 		}
+	case openResultMsg:
+		m.status = ""
+		if msg.err != nil {
+			m.status = msg.err.Error()
+		}
+		return m, nil
 	case highlightLookupMsg:
+		m.status = ""
+		if msg.err != nil {
+			m.status = msg.err.Error()
+		}
 		highlights := msg.highlights
-		selectedTeam := m.list.SelectedItem().(Team)
-		m.highlights[selectedTeam] = highlights
+		if highlights == nil {
+			highlights = []hoop_watcher.Highlight{}
+		}
+		m.highlights[m.list.SelectedItem()] = highlights
 		var rows []table.Row
 		for _, h := range highlights {
-			rows = append(rows, table.Row{h.Title, h.URL.String()})
+			rows = append(rows, table.Row{
+				h.Title,
+				h.Channel,
+				hoop_watcher.FormatDuration(h.Duration),
+				hoop_watcher.FormatViewCount(h.ViewCount),
+				hoop_watcher.FormatPublishedAt(h.PublishedAt),
+				strings.ToUpper(h.Definition),
+				h.URL.String(),
+			})
 		}
 		m.table.SetRows(rows)
 		m.table.Focus()
@@ -148,10 +274,9 @@ func (m model) Update(msg tea.Msg) (n tea.Model, cmd tea.Cmd) {
 
 func (m model) View() string {
 	if m.hasSelectedTeam {
-		selectedTeam := m.list.SelectedItem().(Team)
-		if m.highlights[selectedTeam] != nil {
+		if m.highlights[m.list.SelectedItem()] != nil {
 			{
-				return docStyle.Render(m.table.View())
+				return docStyle.Render(m.table.View() + "\n" + statusStyle.Render(m.status))
 			}
 		}
 	}
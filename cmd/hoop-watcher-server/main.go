@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
@@ -8,6 +9,8 @@ import (
 
 	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
 	"github.com/joho/godotenv"
+	"google.golang.org/api/option"
+	"google.golang.org/api/youtube/v3"
 )
 
 var teamFilePath = path.Join(os.Getenv("HOME"), "bin", hoop_watcher.TeamFileName)
@@ -27,13 +30,25 @@ func main() {
 	if err != nil {
 		log.Fatal("Error occurred initializing data in DB", err)
 	}
-	h := hoop_watcher.NewBaseHandler(db)
-
-	router.HandleFunc("/", h.GetRoot)
-
-	router.HandleFunc("GET /teams", h.GetTeams)
-	router.HandleFunc("GET /teams/{abbrev}", h.GetTeam)
-	router.HandleFunc("GET /teams/{abbrev}/highlights", h.GetTeamHighlights)
+	youtubeClient, err := youtube.NewService(context.Background(), option.WithAPIKey(os.Getenv("YOUTUBE_API_KEY")))
+	if err != nil {
+		log.Fatalf("Error occurred setting up Youtube Client: %v", err)
+	}
+	cacheTTL, err := hoop_watcher.CacheTTLFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	highlightProvider := hoop_watcher.NewCachedHighlightProvider(
+		hoop_watcher.NewChannelFilteredHighlightProvider(
+			hoop_watcher.NewYoutubeHighlightProvider(youtubeClient),
+			hoop_watcher.ChannelFilterFromEnv(),
+		),
+		db,
+		cacheTTL,
+	)
+	h := hoop_watcher.NewBaseHandler(db, highlightProvider)
+
+	h.RegisterRoutes(router)
 
 	log.Printf("Starting server on port 8080")
 	log.Fatal(http.ListenAndServe(":8080", router))
@@ -2,7 +2,10 @@ package hoop_watcher
 
 import (
 	"database/sql"
+	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -19,35 +22,153 @@ CREATE TABLE IF NOT EXISTS teams(
 );
 
 CREATE TABLE IF NOT EXISTS games(
-    id SERIAL PRIMARY KEY,
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
     home_team_id INTEGER NOT NULL REFERENCES teams(id),
     away_team_id INTEGER NOT NULL REFERENCES teams(id),
     date DATE NOT NULL,
+    tip_off DATETIME,
+    home_score INTEGER NOT NULL DEFAULT 0,
+    away_score INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'scheduled',
 	UNIQUE(home_team_id, away_team_id, date)
 );
 
+CREATE INDEX IF NOT EXISTS games_date ON games(date);
+
 CREATE TABLE IF NOT EXISTS game_highlights(
-    id SERIAL PRIMARY KEY,
-    game_id INTEGER NOT NULL REFERENCES games(id),
-    url VARCHAR(255) NOT NULL
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    game_id INTEGER REFERENCES games(id),
+    team_id INTEGER NOT NULL REFERENCES teams(id),
+    opponent_id INTEGER NOT NULL DEFAULT 0,
+    game_date TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL DEFAULT '',
+    title TEXT NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    channel TEXT NOT NULL DEFAULT '',
+    channel_id TEXT NOT NULL DEFAULT '',
+    trusted BOOLEAN NOT NULL DEFAULT 0,
+    published_at DATETIME,
+    score INTEGER NOT NULL DEFAULT 0,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    view_count INTEGER NOT NULL DEFAULT 0,
+    thumbnail_url TEXT NOT NULL DEFAULT '',
+    definition TEXT NOT NULL DEFAULT '',
+    fetched_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS highlight_fetches(
+    team_id INTEGER NOT NULL REFERENCES teams(id),
+    opponent_id INTEGER NOT NULL DEFAULT 0,
+    game_date TEXT NOT NULL DEFAULT '',
+    kind TEXT NOT NULL DEFAULT '',
+    fetched_at DATETIME NOT NULL,
+    PRIMARY KEY(team_id, opponent_id, game_date, kind)
+);
+
+CREATE TABLE IF NOT EXISTS favorite_teams(
+    team_id INTEGER PRIMARY KEY NOT NULL REFERENCES teams(id)
+);
+
+CREATE TABLE IF NOT EXISTS team_aliases(
+    alias TEXT PRIMARY KEY NOT NULL COLLATE NOCASE,
+    team_id INTEGER NOT NULL REFERENCES teams(id)
 );
+
+CREATE INDEX IF NOT EXISTS game_highlights_lookup
+    ON game_highlights(team_id, opponent_id, game_date, kind);
 `
 
+// legacyTables were created by earlier versions with a SERIAL id that SQLite
+// never fills in. Nothing could be written to them, so they are dropped and
+// recreated.
+var legacyTables = []string{"game_highlights", "games"}
+
+// addedColumns are columns added to tables after they were first created.
+var addedColumns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"games", "tip_off", "DATETIME"},
+	{"games", "home_score", "INTEGER NOT NULL DEFAULT 0"},
+	{"games", "away_score", "INTEGER NOT NULL DEFAULT 0"},
+	{"games", "status", "TEXT NOT NULL DEFAULT 'scheduled'"},
+}
+
 type HoopWatcherDB interface {
 	GetAllTeams() ([]NBATeam, error)
-	GetTeamByAbbrev(abbrev string) (NBATeam, error)
-	GetTeamHighlights(teamId int) ([]Highlight, error)
+	TeamLookup
+	GetGamesByDate(date time.Time) ([]Game, error)
+	GetGame(id int) (Game, error)
+	ListGames(filter GameFilter) ([]Game, error)
+	GetGameHighlights(gameId int) ([]Highlight, error)
+	SetTeamFavorite(teamId int, favorite bool) error
+	GetFavoriteTeams() ([]NBATeam, error)
 }
 
 type SqliteHoopWatcherDB struct {
 	db *sql.DB
 }
 
+func dropLegacyTables(db *sql.DB) error {
+	for _, table := range legacyTables {
+		var idType string
+		err := db.QueryRow("SELECT type FROM pragma_table_info(?) WHERE name = 'id'", table).Scan(&idType)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(idType, "SERIAL") {
+			if _, err := db.Exec("DROP TABLE " + table); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func tableHasColumn(db *sql.DB, table string, column string) (bool, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	return count > 0, err
+}
+
+func addMissingColumns(db *sql.DB) error {
+	for _, c := range addedColumns {
+		hasTable, err := tableHasColumn(db, c.table, "id")
+		if err != nil {
+			return err
+		}
+		hasColumn, err := tableHasColumn(db, c.table, c.column)
+		if err != nil {
+			return err
+		}
+		if !hasTable || hasColumn {
+			continue
+		}
+		if _, err := db.Exec("ALTER TABLE " + c.table + " ADD COLUMN " + c.column + " " + c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func NewSqliteHoopWatcherDB(filePath string) (*SqliteHoopWatcherDB, error) {
 	db, err := sql.Open("sqlite3", filePath)
 	if err != nil {
 		return nil, err
 	}
+	// SQLite allows one writer at a time, so concurrent highlight searches
+	// share a single connection instead of failing with "database is locked".
+	db.SetMaxOpenConns(1)
+	if err := dropLegacyTables(db); err != nil {
+		return nil, err
+	}
+	if err := addMissingColumns(db); err != nil {
+		return nil, err
+	}
 	if _, err := db.Exec(initDB); err != nil {
 		return nil, err
 	}
@@ -60,7 +181,10 @@ func (h *SqliteHoopWatcherDB) Close() error {
 }
 
 func (h *SqliteHoopWatcherDB) addAllTeams(filePath string) error {
-	teams := GetNBATeamsFromJSON(filePath)
+	teams, err := GetNBATeamsFromJSON(filePath)
+	if err != nil {
+		return err
+	}
 	stmt, err := h.db.Prepare("INSERT OR IGNORE INTO teams(id, name, full_name, abbreviation, city, conference, division) VALUES (?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return err
@@ -74,10 +198,40 @@ func (h *SqliteHoopWatcherDB) addAllTeams(filePath string) error {
 	return nil
 }
 
+// addTeamAliases replaces the stored aliases of each team in aliases, so
+// aliases removed from the file do not linger.
+func (h *SqliteHoopWatcherDB) addTeamAliases(aliases TeamAliases) error {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("INSERT OR IGNORE INTO team_aliases(alias, team_id) SELECT ?, id FROM teams WHERE abbreviation = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for abbrev, teamAliases := range aliases {
+		if _, err := tx.Exec("DELETE FROM team_aliases WHERE team_id = (SELECT id FROM teams WHERE abbreviation = ?)", abbrev); err != nil {
+			return err
+		}
+		for _, alias := range teamAliases {
+			if _, err := stmt.Exec(alias, abbrev); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
 func (h *SqliteHoopWatcherDB) InitData(teamFilePath string) error {
 	if err := h.addAllTeams(teamFilePath); err != nil {
 		return err
 	}
+	if err := h.addTeamAliases(DefaultTeamAliases); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -87,7 +241,10 @@ func (h *SqliteHoopWatcherDB) GetAllTeams() ([]NBATeam, error) {
 		return []NBATeam{}, err
 	}
 	defer rows.Close()
+	return scanTeams(rows)
+}
 
+func scanTeams(rows *sql.Rows) ([]NBATeam, error) {
 	teams := []NBATeam{}
 	for rows.Next() {
 		team := NBATeam{}
@@ -107,15 +264,494 @@ func (h *SqliteHoopWatcherDB) GetAllTeams() ([]NBATeam, error) {
 	return teams, nil
 }
 
-func (h *SqliteHoopWatcherDB) GetTeamByAbbrev(abbrev string) (NBATeam, error) {
+// SetTeamFavorite marks or unmarks a team as a favorite. Returns
+// ErrTeamNotFound if there is no team with the id.
+func (h *SqliteHoopWatcherDB) SetTeamFavorite(teamId int, favorite bool) error {
+	var exists bool
+	if err := h.db.QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE id = ?)", teamId).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %d", ErrTeamNotFound, teamId)
+	}
+	if favorite {
+		_, err := h.db.Exec("INSERT OR IGNORE INTO favorite_teams(team_id) VALUES (?)", teamId)
+		return err
+	}
+	_, err := h.db.Exec("DELETE FROM favorite_teams WHERE team_id = ?", teamId)
+	return err
+}
+
+func (h *SqliteHoopWatcherDB) GetFavoriteTeams() ([]NBATeam, error) {
+	rows, err := h.db.Query("SELECT teams.* FROM teams JOIN favorite_teams ON favorite_teams.team_id = teams.id ORDER BY teams.full_name")
+	if err != nil {
+		return []NBATeam{}, err
+	}
+	defer rows.Close()
+	return scanTeams(rows)
+}
+
+// scanTeam scans a single team, returning ErrTeamNotFound if there is none.
+func scanTeam(row *sql.Row, query any) (NBATeam, error) {
 	var team NBATeam
-	row := h.db.QueryRow("SELECT * FROM teams WHERE abbrev = ?", strings.ToUpper(abbrev))
-	if err := row.Scan(&team.Id, &team.Name, &team.Abbreviation); err != nil {
+	if err := row.Scan(
+		&team.Id,
+		&team.Name,
+		&team.FullName,
+		&team.Abbreviation,
+		&team.City,
+		&team.Conference,
+		&team.Division,
+	); err == sql.ErrNoRows {
+		return NBATeam{}, fmt.Errorf("%w: %v", ErrTeamNotFound, query)
+	} else if err != nil {
 		return NBATeam{}, err
 	}
 	return team, nil
 }
 
+func (h *SqliteHoopWatcherDB) GetTeamByID(id int) (NBATeam, error) {
+	return scanTeam(h.db.QueryRow("SELECT * FROM teams WHERE id = ?", id), id)
+}
+
+func (h *SqliteHoopWatcherDB) GetTeamByAbbrev(abbrev string) (NBATeam, error) {
+	abbrev = strings.TrimSpace(abbrev)
+	return scanTeam(h.db.QueryRow("SELECT * FROM teams WHERE abbreviation = ?", strings.ToUpper(abbrev)), abbrev)
+}
+
+// GetTeamByName finds a team by its name ("Knicks"), full name ("New York
+// Knicks") or one of its aliases ("Sixers"), ignoring case.
+func (h *SqliteHoopWatcherDB) GetTeamByName(name string) (NBATeam, error) {
+	name = strings.TrimSpace(name)
+	row := h.db.QueryRow(
+		`SELECT * FROM teams
+		WHERE name = ?1 COLLATE NOCASE OR full_name = ?1 COLLATE NOCASE
+		OR id = (SELECT team_id FROM team_aliases WHERE alias = ?1)
+		ORDER BY id LIMIT 1`,
+		name,
+	)
+	return scanTeam(row, name)
+}
+
+const highlightColumns = "title, url, channel, channel_id, trusted, published_at, score, duration_seconds, view_count, thumbnail_url, definition"
+
+func scanHighlights(rows *sql.Rows) ([]Highlight, error) {
+	highlights := []Highlight{}
+	for rows.Next() {
+		var highlight Highlight
+		var rawURL string
+		var publishedAt sql.NullTime
+		var durationSeconds int64
+		if err := rows.Scan(
+			&highlight.Title,
+			&rawURL,
+			&highlight.Channel,
+			&highlight.ChannelId,
+			&highlight.Trusted,
+			&publishedAt,
+			&highlight.Score,
+			&durationSeconds,
+			&highlight.ViewCount,
+			&highlight.ThumbnailURL,
+			&highlight.Definition,
+		); err != nil {
+			return []Highlight{}, err
+		}
+		parsedURL, err := url.Parse(rawURL)
+		if err != nil {
+			return []Highlight{}, err
+		}
+		highlight.URL = *parsedURL
+		highlight.PublishedAt = publishedAt.Time
+		highlight.Duration = time.Duration(durationSeconds) * time.Second
+		highlights = append(highlights, highlight)
+	}
+	return highlights, rows.Err()
+}
+
 func (h *SqliteHoopWatcherDB) GetTeamHighlights(id int) ([]Highlight, error) {
-	return []Highlight{}, nil
+	rows, err := h.db.Query(
+		"SELECT "+highlightColumns+" FROM game_highlights WHERE team_id = ? OR opponent_id = ? ORDER BY fetched_at DESC, score DESC",
+		id,
+		id,
+	)
+	if err != nil {
+		return []Highlight{}, err
+	}
+	defer rows.Close()
+	return scanHighlights(rows)
+}
+
+// GetCachedHighlights returns the highlights cached for the query's game if
+// it was searched after fetchedAfter. found is false when it needs searching
+// again, and true with no highlights when the last search found none.
+func (h *SqliteHoopWatcherDB) GetCachedHighlights(query HighlightQuery, fetchedAfter time.Time) (highlights []Highlight, found bool, err error) {
+	teamId, opponentId, gameDate := highlightCacheKey(query)
+	var fetches int
+	if err := h.db.QueryRow(
+		"SELECT COUNT(*) FROM highlight_fetches WHERE team_id = ? AND opponent_id = ? AND game_date = ? AND kind = ? AND fetched_at >= ?",
+		teamId,
+		opponentId,
+		gameDate,
+		string(query.Kind),
+		fetchedAfter.UTC(),
+	).Scan(&fetches); err != nil {
+		return []Highlight{}, false, err
+	}
+	if fetches == 0 {
+		return []Highlight{}, false, nil
+	}
+
+	rows, err := h.db.Query(
+		"SELECT "+highlightColumns+" FROM game_highlights WHERE team_id = ? AND opponent_id = ? AND game_date = ? AND kind = ? ORDER BY id",
+		teamId,
+		opponentId,
+		gameDate,
+		string(query.Kind),
+	)
+	if err != nil {
+		return []Highlight{}, false, err
+	}
+	defer rows.Close()
+	highlights, err = scanHighlights(rows)
+	return highlights, err == nil, err
+}
+
+// CacheHighlights replaces the cached highlights of the query's game, linking
+// them to the game when it is in the games table. The search is recorded even
+// when it found nothing, so an empty result is cached too.
+func (h *SqliteHoopWatcherDB) CacheHighlights(query HighlightQuery, highlights []Highlight, fetchedAt time.Time) error {
+	teamId, opponentId, gameDate := highlightCacheKey(query)
+	tx, err := h.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"DELETE FROM game_highlights WHERE team_id = ? AND opponent_id = ? AND game_date = ? AND kind = ?",
+		teamId,
+		opponentId,
+		gameDate,
+		string(query.Kind),
+	); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		"INSERT OR REPLACE INTO highlight_fetches(team_id, opponent_id, game_date, kind, fetched_at) VALUES (?, ?, ?, ?, ?)",
+		teamId,
+		opponentId,
+		gameDate,
+		string(query.Kind),
+		fetchedAt.UTC(),
+	); err != nil {
+		return err
+	}
+	var gameId sql.NullInt64
+	if opponentId != 0 && gameDate != "" {
+		err := tx.QueryRow(
+			`SELECT id FROM games WHERE date = ?
+			AND ((home_team_id = ? AND away_team_id = ?) OR (home_team_id = ? AND away_team_id = ?))`,
+			gameDate,
+			teamId,
+			opponentId,
+			opponentId,
+			teamId,
+		).Scan(&gameId)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+	}
+	stmt, err := tx.Prepare("INSERT INTO game_highlights(game_id, team_id, opponent_id, game_date, kind, " + highlightColumns + ", fetched_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, highlight := range highlights {
+		var publishedAt sql.NullTime
+		if !highlight.PublishedAt.IsZero() {
+			publishedAt = sql.NullTime{Time: highlight.PublishedAt, Valid: true}
+		}
+		if _, err := stmt.Exec(
+			gameId,
+			teamId,
+			opponentId,
+			gameDate,
+			string(query.Kind),
+			highlight.Title,
+			highlight.URL.String(),
+			highlight.Channel,
+			highlight.ChannelId,
+			highlight.Trusted,
+			publishedAt,
+			highlight.Score,
+			int64(highlight.Duration/time.Second),
+			highlight.ViewCount,
+			highlight.ThumbnailURL,
+			highlight.Definition,
+			fetchedAt.UTC(),
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+const gameColumns = `g.id, g.date, g.tip_off, g.home_score, g.away_score, g.status,
+	home.id, home.name, home.full_name, home.abbreviation, home.city, home.conference, home.division,
+	away.id, away.name, away.full_name, away.abbreviation, away.city, away.conference, away.division`
+
+const gameTables = `games g
+	JOIN teams home ON home.id = g.home_team_id
+	JOIN teams away ON away.id = g.away_team_id`
+
+func scanGame(row interface{ Scan(...any) error }) (Game, error) {
+	var game Game
+	var tipOff sql.NullTime
+	var status string
+	if err := row.Scan(
+		&game.Id,
+		&game.Date,
+		&tipOff,
+		&game.HomeScore,
+		&game.AwayScore,
+		&status,
+		&game.HomeTeam.Id,
+		&game.HomeTeam.Name,
+		&game.HomeTeam.FullName,
+		&game.HomeTeam.Abbreviation,
+		&game.HomeTeam.City,
+		&game.HomeTeam.Conference,
+		&game.HomeTeam.Division,
+		&game.AwayTeam.Id,
+		&game.AwayTeam.Name,
+		&game.AwayTeam.FullName,
+		&game.AwayTeam.Abbreviation,
+		&game.AwayTeam.City,
+		&game.AwayTeam.Conference,
+		&game.AwayTeam.Division,
+	); err != nil {
+		return Game{}, err
+	}
+	game.TipOff = tipOff.Time
+	game.Status = GameStatus(status)
+	return game, nil
+}
+
+func scanGames(rows *sql.Rows) ([]Game, error) {
+	games := []Game{}
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return []Game{}, err
+		}
+		games = append(games, game)
+	}
+	return games, rows.Err()
+}
+
+// AddGame inserts a game, or updates the game the same teams play on the same
+// date with the tip-off, score and status set on game, keeping the stored ones
+// that are not. It also links the highlights already cached for the game.
+func (h *SqliteHoopWatcherDB) AddGame(game Game) (Game, error) {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return Game{}, err
+	}
+	defer tx.Rollback()
+
+	id, err := addGame(tx, game)
+	if err != nil {
+		return Game{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return Game{}, err
+	}
+	return h.GetGame(id)
+}
+
+// AddGames adds each game like AddGame, all or none of them.
+func (h *SqliteHoopWatcherDB) AddGames(games []Game) error {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, game := range games {
+		if _, err := addGame(tx, game); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func addGame(tx *sql.Tx, game Game) (int, error) {
+	var tipOff sql.NullTime
+	if !game.TipOff.IsZero() {
+		tipOff = sql.NullTime{Time: game.TipOff.UTC(), Valid: true}
+	}
+	var homeScore, awayScore sql.NullInt64
+	if game.HomeScore != 0 || game.AwayScore != 0 {
+		homeScore = sql.NullInt64{Int64: int64(game.HomeScore), Valid: true}
+		awayScore = sql.NullInt64{Int64: int64(game.AwayScore), Valid: true}
+	}
+	status := sql.NullString{String: string(game.Status), Valid: game.Status != ""}
+
+	row := tx.QueryRow(
+		`INSERT INTO games(home_team_id, away_team_id, date, tip_off, home_score, away_score, status)
+		VALUES (?1, ?2, ?3, ?4, COALESCE(?5, 0), COALESCE(?6, 0), COALESCE(?7, ?8))
+		ON CONFLICT(home_team_id, away_team_id, date) DO UPDATE SET
+			tip_off = COALESCE(?4, games.tip_off),
+			home_score = COALESCE(?5, games.home_score),
+			away_score = COALESCE(?6, games.away_score),
+			status = COALESCE(?7, games.status)
+		RETURNING id`,
+		game.HomeTeam.Id,
+		game.AwayTeam.Id,
+		game.Date.Format(DAILY_DATE_FORMAT),
+		tipOff,
+		homeScore,
+		awayScore,
+		status,
+		string(GameScheduled),
+	)
+	var id int
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	// Highlights cached before the game was added are linked to it now.
+	teamId, opponentId, gameDate := highlightCacheKey(game.HighlightQuery(AnyHighlights))
+	if _, err := tx.Exec(
+		"UPDATE game_highlights SET game_id = ? WHERE game_id IS NULL AND team_id = ? AND opponent_id = ? AND game_date = ?",
+		id,
+		teamId,
+		opponentId,
+		gameDate,
+	); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (h *SqliteHoopWatcherDB) GetGame(id int) (Game, error) {
+	row := h.db.QueryRow("SELECT "+gameColumns+" FROM "+gameTables+" WHERE g.id = ?", id)
+	return scanGame(row)
+}
+
+func (h *SqliteHoopWatcherDB) GetGamesByDate(date time.Time) ([]Game, error) {
+	rows, err := h.db.Query(
+		"SELECT "+gameColumns+" FROM "+gameTables+" WHERE g.date = ? ORDER BY g.tip_off, g.id",
+		date.Format(DAILY_DATE_FORMAT),
+	)
+	if err != nil {
+		return []Game{}, err
+	}
+	defer rows.Close()
+	return scanGames(rows)
+}
+
+// GameFilter narrows down the games ListGames returns. Zero fields match
+// every game.
+type GameFilter struct {
+	TeamId int
+	// From and To are the first and last dates to include.
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+// ListGames returns the games matching filter, earliest first.
+func (h *SqliteHoopWatcherDB) ListGames(filter GameFilter) ([]Game, error) {
+	conditions := []string{"1 = 1"}
+	args := []any{}
+	if filter.TeamId != 0 {
+		conditions = append(conditions, "(g.home_team_id = ? OR g.away_team_id = ?)")
+		args = append(args, filter.TeamId, filter.TeamId)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "g.date >= ?")
+		args = append(args, filter.From.Format(DAILY_DATE_FORMAT))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "g.date <= ?")
+		args = append(args, filter.To.Format(DAILY_DATE_FORMAT))
+	}
+	limit := -1
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+	args = append(args, limit, filter.Offset)
+
+	rows, err := h.db.Query(
+		"SELECT "+gameColumns+" FROM "+gameTables+" WHERE "+strings.Join(conditions, " AND ")+" ORDER BY g.date, g.tip_off, g.id LIMIT ? OFFSET ?",
+		args...,
+	)
+	if err != nil {
+		return []Game{}, err
+	}
+	defer rows.Close()
+	return scanGames(rows)
+}
+
+// OpeningNight returns the date of the first game of season in the games
+// table. Returns sql.ErrNoRows if none of its games have been imported.
+func (h *SqliteHoopWatcherDB) OpeningNight(season int) (time.Time, error) {
+	seasonStart := time.Date(season, time.August, 1, 0, 0, 0, 0, time.UTC)
+	var date sql.NullString
+	if err := h.db.QueryRow(
+		"SELECT MIN(date) FROM games WHERE date >= ? AND date < ?",
+		seasonStart.Format(DAILY_DATE_FORMAT),
+		seasonStart.AddDate(1, 0, 0).Format(DAILY_DATE_FORMAT),
+	).Scan(&date); err != nil {
+		return time.Time{}, err
+	}
+	if !date.Valid || len(date.String) < len(DAILY_DATE_FORMAT) {
+		return time.Time{}, sql.ErrNoRows
+	}
+	return time.Parse(DAILY_DATE_FORMAT, date.String[:len(DAILY_DATE_FORMAT)])
+}
+
+// GetGamesForTeam returns the team's games, most recent first.
+func (h *SqliteHoopWatcherDB) GetGamesForTeam(teamId int) ([]Game, error) {
+	rows, err := h.db.Query(
+		"SELECT "+gameColumns+" FROM "+gameTables+" WHERE g.home_team_id = ? OR g.away_team_id = ? ORDER BY g.date DESC, g.id DESC",
+		teamId,
+		teamId,
+	)
+	if err != nil {
+		return []Game{}, err
+	}
+	defer rows.Close()
+	return scanGames(rows)
+}
+
+// GetGameByTeamsAndDate finds the game two teams play on date, whichever of
+// them is home.
+func (h *SqliteHoopWatcherDB) GetGameByTeamsAndDate(teamId int, opponentId int, date time.Time) (Game, error) {
+	row := h.db.QueryRow(
+		"SELECT "+gameColumns+" FROM "+gameTables+` WHERE g.date = ?
+		AND ((g.home_team_id = ? AND g.away_team_id = ?) OR (g.home_team_id = ? AND g.away_team_id = ?))`,
+		date.Format(DAILY_DATE_FORMAT),
+		teamId,
+		opponentId,
+		opponentId,
+		teamId,
+	)
+	return scanGame(row)
+}
+
+// GetGameHighlights returns the highlights cached for a game under any kind,
+// so a video found for several kinds is returned once for each.
+func (h *SqliteHoopWatcherDB) GetGameHighlights(gameId int) ([]Highlight, error) {
+	rows, err := h.db.Query(
+		"SELECT "+highlightColumns+" FROM game_highlights WHERE game_id = ? ORDER BY id",
+		gameId,
+	)
+	if err != nil {
+		return []Highlight{}, err
+	}
+	defer rows.Close()
+	return scanHighlights(rows)
 }
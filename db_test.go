@@ -0,0 +1,132 @@
+package hoop_watcher_test
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+func TestSqliteFavoriteTeams(t *testing.T) {
+	t.Run("it stores favorite teams", func(t *testing.T) {
+		db := newTestDB(t)
+		for _, teamId := range []int{20, 2} {
+			if err := db.SetTeamFavorite(teamId, true); err != nil {
+				t.Fatalf("Found err: %v", err)
+			}
+		}
+		if err := db.SetTeamFavorite(20, true); err != nil {
+			t.Fatalf("Found err favoriting a team twice: %v", err)
+		}
+
+		got, err := db.GetFavoriteTeams()
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 2 || got[0].FullName != "Boston Celtics" || got[1].FullName != "New York Knicks" {
+			t.Errorf("got %v, want the Celtics and the Knicks", got)
+		}
+
+		if err := db.SetTeamFavorite(2, false); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		got, err = db.GetFavoriteTeams()
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 1 || got[0].Id != 20 {
+			t.Errorf("got %v, want only the Knicks", got)
+		}
+	})
+
+	t.Run("it rejects unknown teams", func(t *testing.T) {
+		db := newTestDB(t)
+		if err := db.SetTeamFavorite(99, true); !errors.Is(err, hoop_watcher.ErrTeamNotFound) {
+			t.Errorf("got err %v, want %v", err, hoop_watcher.ErrTeamNotFound)
+		}
+	})
+}
+
+func TestSqliteTeamLookup(t *testing.T) {
+	db := newTestDB(t)
+
+	t.Run("it looks up teams by abbreviation, id and name", func(t *testing.T) {
+		team, err := db.GetTeamByAbbrev("nyk")
+		if err != nil || team.FullName != "New York Knicks" {
+			t.Errorf("got %v and err %v, want the Knicks", team, err)
+		}
+		team, err = db.GetTeamByID(20)
+		if err != nil || team.Abbreviation != "NYK" {
+			t.Errorf("got %v and err %v, want the Knicks", team, err)
+		}
+		for _, name := range []string{"Knicks", "new york knicks"} {
+			team, err = db.GetTeamByName(name)
+			if err != nil || team.Abbreviation != "NYK" {
+				t.Errorf("got %v and err %v for %q, want the Knicks", team, err, name)
+			}
+		}
+	})
+
+	t.Run("it returns ErrTeamNotFound for unknown teams", func(t *testing.T) {
+		if _, err := db.GetTeamByAbbrev("SEA"); !errors.Is(err, hoop_watcher.ErrTeamNotFound) {
+			t.Errorf("got err %v, want %v", err, hoop_watcher.ErrTeamNotFound)
+		}
+		if _, err := db.GetTeamByID(99); !errors.Is(err, hoop_watcher.ErrTeamNotFound) {
+			t.Errorf("got err %v, want %v", err, hoop_watcher.ErrTeamNotFound)
+		}
+		if _, err := db.GetTeamByName("Supersonics"); !errors.Is(err, hoop_watcher.ErrTeamNotFound) {
+			t.Errorf("got err %v, want %v", err, hoop_watcher.ErrTeamNotFound)
+		}
+	})
+
+	t.Run("it resolves ids, abbreviations, names and aliases", func(t *testing.T) {
+		cases := map[string]string{
+			"20":             "NYK",
+			"PHX":            "PHX",
+			"PHO":            "PHX",
+			"Sixers":         "PHI",
+			"dubs":           "GSW",
+			"Cavs":           "CLE",
+			"NOLA":           "NOP",
+			" Trail Blazers": "POR",
+		}
+		for query, want := range cases {
+			team, err := hoop_watcher.ResolveTeam(db, query)
+			if err != nil {
+				t.Errorf("got err %v for %q", err, query)
+				continue
+			}
+			if team.Abbreviation != want {
+				t.Errorf("got %s for %q, want %s", team.Abbreviation, query, want)
+			}
+		}
+	})
+	t.Run("it drops aliases removed from the aliases file", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "hoop-watcher-test.db")
+		db, err := hoop_watcher.NewSqliteHoopWatcherDB(filePath)
+		if err != nil {
+			t.Fatalf("could not create test db: %v", err)
+		}
+		defer db.Close()
+		if err := db.InitData(teamFilePath); err != nil {
+			t.Fatalf("could not init test db: %v", err)
+		}
+		raw, err := sql.Open("sqlite3", filePath)
+		if err != nil {
+			t.Fatalf("could not open test db: %v", err)
+		}
+		defer raw.Close()
+		if _, err := raw.Exec("INSERT INTO team_aliases(alias, team_id) SELECT 'New Orleans Hornets', id FROM teams WHERE abbreviation = 'NOP'"); err != nil {
+			t.Fatalf("could not add alias: %v", err)
+		}
+
+		if err := db.InitData(teamFilePath); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if team, err := db.GetTeamByName("New Orleans Hornets"); !errors.Is(err, hoop_watcher.ErrTeamNotFound) {
+			t.Errorf("got %v and err %v, want the removed alias gone", team, err)
+		}
+	})
+}
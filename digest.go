@@ -0,0 +1,191 @@
+package hoop_watcher
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type DigestFormat string
+
+const (
+	DigestText     DigestFormat = "text"
+	DigestMarkdown DigestFormat = "markdown"
+	DigestHTML     DigestFormat = "html"
+)
+
+// ParseDigestFormat reads a digest format, falling back to the extension of
+// outputPath when formatStr is empty.
+func ParseDigestFormat(formatStr string, outputPath string) (DigestFormat, error) {
+	if formatStr == "" {
+		switch strings.ToLower(filepath.Ext(outputPath)) {
+		case ".md", ".markdown":
+			return DigestMarkdown, nil
+		case ".html", ".htm":
+			return DigestHTML, nil
+		default:
+			return DigestText, nil
+		}
+	}
+	switch format := DigestFormat(strings.ToLower(formatStr)); format {
+	case DigestText, DigestMarkdown, DigestHTML:
+		return format, nil
+	case "md":
+		return DigestMarkdown, nil
+	}
+	return DigestText, fmt.Errorf("Invalid digest format %q, expected one of text, markdown or html", formatStr)
+}
+
+// DigestStore is where the digest finds favorite teams and their games.
+type DigestStore interface {
+	GetFavoriteTeams() ([]NBATeam, error)
+	GetGamesForTeam(teamId int) ([]Game, error)
+}
+
+// DigestEntry is the best highlight of a favorite team's most recent game.
+// Game is nil when the games table has no game for the team, and Highlight is
+// nil when nothing was found.
+type DigestEntry struct {
+	Team      NBATeam
+	Game      *Game
+	Highlight *Highlight
+	Err       error
+}
+
+// mostRecentGame returns the latest of games dated before today. Nothing
+// records when a game ends, so a game counts as over only once its date has
+// passed: a morning digest gets last night's result, not tonight's game.
+// games must be sorted most recent first.
+func mostRecentGame(games []Game, now time.Time) *Game {
+	today := dateOnly(now)
+	for _, game := range games {
+		if game.Date.Before(today) {
+			return &game
+		}
+	}
+	return nil
+}
+
+// BuildDigest finds the best highlight of each favorite team's most recent
+// game. Teams without a game in the games table get their latest highlights.
+func BuildDigest(store DigestStore, provider HighlightProvider, kind HighlightKind, now time.Time) ([]DigestEntry, error) {
+	favoriteTeams, err := store.GetFavoriteTeams()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []DigestEntry{}
+	for _, team := range favoriteTeams {
+		entry := DigestEntry{Team: team}
+		games, err := store.GetGamesForTeam(team.Id)
+		if err != nil {
+			entry.Err = err
+			entries = append(entries, entry)
+			continue
+		}
+
+		query := HighlightQuery{Teams: []NBATeam{team}, Kind: kind}
+		if entry.Game = mostRecentGame(games, now); entry.Game != nil {
+			query = entry.Game.HighlightQuery(kind)
+		}
+		highlights, err := FindHighlights(query, provider)
+		if err != nil {
+			entry.Err = err
+		} else if len(highlights) > 0 {
+			entry.Highlight = &highlights[0]
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (e DigestEntry) gameLabel() string {
+	if e.Game == nil {
+		return "Latest game"
+	}
+	return fmt.Sprintf("%s on %s", e.Game, e.Game.Date.Format(HUMAN_DATE_FORMAT))
+}
+
+func (e DigestEntry) summary() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("Error occurred fetching highlights: %v", e.Err)
+	case e.Highlight == nil:
+		return "No highlights found"
+	default:
+		return fmt.Sprintf("%s (%s, %s)", e.Highlight.Title, e.Highlight.Channel, FormatDuration(e.Highlight.Duration))
+	}
+}
+
+func writeDigestText(out io.Writer, entries []DigestEntry) error {
+	for _, entry := range entries {
+		fmt.Fprintf(out, "%s | %s\n  %s\n", entry.Team.FullName, entry.gameLabel(), entry.summary())
+		if entry.Highlight != nil {
+			fmt.Fprintf(out, "  %s\n", entry.Highlight.URL.String())
+		}
+	}
+	return nil
+}
+
+func writeDigestMarkdown(out io.Writer, entries []DigestEntry, date time.Time) error {
+	fmt.Fprintf(out, "# Hoop Watcher digest for %s\n\n", date.Format(HUMAN_DATE_FORMAT))
+	for _, entry := range entries {
+		fmt.Fprintf(out, "## %s\n\n%s\n\n", entry.Team.FullName, entry.gameLabel())
+		if entry.Highlight != nil {
+			fmt.Fprintf(out, "[%s](%s) - %s, %s\n\n", entry.Highlight.Title, entry.Highlight.URL.String(), entry.Highlight.Channel, FormatDuration(entry.Highlight.Duration))
+		} else {
+			fmt.Fprintf(out, "%s\n\n", entry.summary())
+		}
+	}
+	return nil
+}
+
+var digestHTMLTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Hoop Watcher digest for {{.Date}}</title></head>
+<body>
+<h1>Hoop Watcher digest for {{.Date}}</h1>
+{{range .Entries}}<h2>{{.Team.FullName}}</h2>
+<p>{{.GameLabel}}</p>
+{{if .Highlight}}<p><a href="{{.Highlight.URL.String}}">{{if .Highlight.ThumbnailURL}}<img src="{{.Highlight.ThumbnailURL}}" alt="" width="320"><br>{{end}}{{.Highlight.Title}}</a> - {{.Highlight.Channel}}, {{.Duration}}</p>
+{{else}}<p>{{.Summary}}</p>
+{{end}}{{end}}</body>
+</html>
+`))
+
+type digestHTMLEntry struct {
+	DigestEntry
+	GameLabel string
+	Summary   string
+	Duration  string
+}
+
+func writeDigestHTML(out io.Writer, entries []DigestEntry, date time.Time) error {
+	htmlEntries := []digestHTMLEntry{}
+	for _, entry := range entries {
+		htmlEntry := digestHTMLEntry{DigestEntry: entry, GameLabel: entry.gameLabel(), Summary: entry.summary()}
+		if entry.Highlight != nil {
+			htmlEntry.Duration = FormatDuration(entry.Highlight.Duration)
+		}
+		htmlEntries = append(htmlEntries, htmlEntry)
+	}
+	return digestHTMLTemplate.Execute(out, struct {
+		Date    string
+		Entries []digestHTMLEntry
+	}{date.Format(HUMAN_DATE_FORMAT), htmlEntries})
+}
+
+// WriteDigest renders the digest for date in format.
+func WriteDigest(out io.Writer, entries []DigestEntry, format DigestFormat, date time.Time) error {
+	switch format {
+	case DigestMarkdown:
+		return writeDigestMarkdown(out, entries, date)
+	case DigestHTML:
+		return writeDigestHTML(out, entries, date)
+	default:
+		return writeDigestText(out, entries)
+	}
+}
@@ -0,0 +1,124 @@
+package hoop_watcher_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+func TestDigest(t *testing.T) {
+	gameDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := gameDate.Add(36 * time.Hour)
+
+	newDigestDB := func(t *testing.T) *hoop_watcher.SqliteHoopWatcherDB {
+		db := newTestDB(t)
+		for _, teamId := range []int{20, 2} {
+			if err := db.SetTeamFavorite(teamId, true); err != nil {
+				t.Fatalf("Found err: %v", err)
+			}
+		}
+		games := []hoop_watcher.Game{
+			{HomeTeam: hoop_watcher.NBATeam{Id: 16}, AwayTeam: hoop_watcher.NBATeam{Id: 20}, Date: gameDate},
+			{HomeTeam: hoop_watcher.NBATeam{Id: 20}, AwayTeam: hoop_watcher.NBATeam{Id: 2}, Date: gameDate.AddDate(0, 0, 3)},
+		}
+		for _, game := range games {
+			if _, err := db.AddGame(game); err != nil {
+				t.Fatalf("Found err: %v", err)
+			}
+		}
+		return db
+	}
+
+	t.Run("it finds the best highlight of each favorite team's most recent game", func(t *testing.T) {
+		db := newDigestDB(t)
+		provider := &fakeHighlightProvider{highlights: []hoop_watcher.Highlight{
+			{Title: "Knicks vs Heat reaction", URL: mustParseURL(t, "https://www.youtube.com/watch?v=react")},
+			{Title: "Knicks vs Heat Full Game Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=abc"), Channel: "NBA"},
+		}}
+
+		entries, err := hoop_watcher.BuildDigest(db, provider, hoop_watcher.AnyHighlights, now)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("got %d entries, want 2", len(entries))
+		}
+
+		celtics, knicks := entries[0], entries[1]
+		if celtics.Game != nil {
+			t.Errorf("got game %v for the Celtics, want none since their game is in the future", celtics.Game)
+		}
+		if knicks.Game == nil || !knicks.Game.Date.Equal(gameDate) {
+			t.Fatalf("got game %v for the Knicks, want the game on %v", knicks.Game, gameDate)
+		}
+		if knicks.Highlight == nil || knicks.Highlight.URL.Query().Get("v") != "abc" {
+			t.Errorf("got highlight %v, want the full game highlights", knicks.Highlight)
+		}
+	})
+
+	t.Run("it skips tonight's game on a morning run", func(t *testing.T) {
+		db := newDigestDB(t)
+		provider := &fakeHighlightProvider{highlights: []hoop_watcher.Highlight{
+			{Title: "Knicks vs Heat Full Game Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=abc"), Channel: "NBA"},
+		}}
+		morning := gameDate.AddDate(0, 0, 3).Add(7 * time.Hour)
+
+		entries, err := hoop_watcher.BuildDigest(db, provider, hoop_watcher.AnyHighlights, morning)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("got %d entries, want 2", len(entries))
+		}
+		celtics, knicks := entries[0], entries[1]
+		if celtics.Game != nil {
+			t.Errorf("got game %v for the Celtics, want none since they play tonight", celtics.Game)
+		}
+		if knicks.Game == nil || !knicks.Game.Date.Equal(gameDate) {
+			t.Errorf("got game %v for the Knicks, want the game on %v", knicks.Game, gameDate)
+		}
+	})
+
+	t.Run("it writes the digest in each format", func(t *testing.T) {
+		db := newDigestDB(t)
+		provider := &fakeHighlightProvider{highlights: []hoop_watcher.Highlight{
+			{Title: "Knicks vs Heat <Full Game> Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=abc"), Channel: "NBA"},
+		}}
+		entries, err := hoop_watcher.BuildDigest(db, provider, hoop_watcher.AnyHighlights, now)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		cases := map[hoop_watcher.DigestFormat]string{
+			hoop_watcher.DigestText:     "New York Knicks | NYK @ MIA on January 1, 2023\n  Knicks vs Heat <Full Game> Highlights (NBA, -)",
+			hoop_watcher.DigestMarkdown: "[Knicks vs Heat <Full Game> Highlights](https://www.youtube.com/watch?v=abc)",
+			hoop_watcher.DigestHTML:     `<a href="https://www.youtube.com/watch?v=abc">Knicks vs Heat &lt;Full Game&gt; Highlights</a>`,
+		}
+		for format, want := range cases {
+			out := &bytes.Buffer{}
+			if err := hoop_watcher.WriteDigest(out, entries, format, now); err != nil {
+				t.Fatalf("Found err: %v", err)
+			}
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s digest %q is missing %q", format, out.String(), want)
+			}
+		}
+	})
+
+	t.Run("it picks the format from the output file", func(t *testing.T) {
+		cases := map[string]hoop_watcher.DigestFormat{
+			"":            hoop_watcher.DigestText,
+			"digest.md":   hoop_watcher.DigestMarkdown,
+			"digest.html": hoop_watcher.DigestHTML,
+		}
+		for outputPath, want := range cases {
+			got, err := hoop_watcher.ParseDigestFormat("", outputPath)
+			if err != nil || got != want {
+				t.Errorf("got %s, %v for %q, want %s", got, err, outputPath, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package hoop_watcher
+
+import "errors"
+
+var (
+	// ErrTeamNotFound is returned when a team lookup matches no NBA team.
+	ErrTeamNotFound = errors.New("Team not found")
+	// ErrQuotaExceeded is returned when the highlight provider has used up
+	// its API quota.
+	ErrQuotaExceeded = errors.New("Highlight search quota exceeded")
+	// ErrProviderUnavailable is returned when the highlight provider could
+	// not be reached or failed to answer.
+	ErrProviderUnavailable = errors.New("Highlight provider unavailable")
+	// ErrInvalidTeamsFile is returned when the NBA teams file is missing or
+	// cannot be parsed.
+	ErrInvalidTeamsFile = errors.New("Invalid NBA teams file")
+)
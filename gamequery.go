@@ -0,0 +1,306 @@
+package hoop_watcher
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GameQuery is a game described in plain words, e.g. "warriors at lakers
+// last night".
+type GameQuery struct {
+	// Teams are in the order they were mentioned, so "warriors at lakers"
+	// lists the visitors first like Game.Teams does.
+	Teams []NBATeam
+	// Date is zero when the query mentions no date.
+	Date time.Time
+}
+
+func (q GameQuery) HighlightQuery(kind HighlightKind) HighlightQuery {
+	return HighlightQuery{Teams: q.Teams, Date: q.Date, Kind: kind}
+}
+
+// SeasonCalendar looks up dates that move from season to season.
+type SeasonCalendar interface {
+	// OpeningNight returns the first game date of the season starting in
+	// the fall of season.
+	OpeningNight(season int) (time.Time, error)
+}
+
+// SeasonOf returns the year the NBA season being played on date started.
+func SeasonOf(date time.Time) int {
+	if date.Month() >= time.August {
+		return date.Year()
+	}
+	return date.Year() - 1
+}
+
+// ErrUnknownDate is returned when a date phrase cannot be turned into a date,
+// e.g. "opening night" before the schedule is imported.
+var ErrUnknownDate = errors.New("Unknown date")
+
+var monthPattern = `(jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)`
+
+var weekdayPattern = `(sunday|monday|tuesday|wednesday|thursday|friday|saturday)`
+
+var monthsByPrefix = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+var weekdaysByName = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// dateOnly drops the time of day from t.
+func dateOnly(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// mostRecent returns the latest date on or before today that date(year)
+// gives, trying this year and then last year.
+func mostRecent(today time.Time, date func(year int) time.Time) time.Time {
+	if d := date(today.Year()); !d.After(today) {
+		return d
+	}
+	return date(today.Year() - 1)
+}
+
+// nthWeekday returns the nth weekday of month, e.g. the 3rd Monday of
+// January.
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// holidays are the days the NBA plays on that fans refer to by name.
+var holidays = []struct {
+	pattern *regexp.Regexp
+	date    func(year int) time.Time
+}{
+	{regexp.MustCompile(`\bchristmas(?: day)?\b`), func(year int) time.Time {
+		return time.Date(year, time.December, 25, 0, 0, 0, 0, time.UTC)
+	}},
+	{regexp.MustCompile(`\bnew year'?s eve\b`), func(year int) time.Time {
+		return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	}},
+	{regexp.MustCompile(`\bnew year'?s(?: day)?\b`), func(year int) time.Time {
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}},
+	{regexp.MustCompile(`\b(?:mlk|martin luther king(?: jr)?) day\b`), func(year int) time.Time {
+		return nthWeekday(year, time.January, time.Monday, 3)
+	}},
+	{regexp.MustCompile(`\bthanksgiving\b`), func(year int) time.Time {
+		return nthWeekday(year, time.November, time.Thursday, 4)
+	}},
+}
+
+var (
+	isoDatePattern      = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	numericDatePattern  = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})(?:/(\d{2}|\d{4}))?\b`)
+	monthDatePattern    = regexp.MustCompile(`\b` + monthPattern + ` (\d{1,2})(?:st|nd|rd|th)?(?:,? (\d{4}))?\b`)
+	daysAgoPattern      = regexp.MustCompile(`\b(\d+) days? ago\b`)
+	yesterdayPattern    = regexp.MustCompile(`\b(?:last night|yesterday)\b`)
+	todayPattern        = regexp.MustCompile(`\b(?:today|tonight)\b`)
+	weekdayDatePattern  = regexp.MustCompile(`\b(?:(?:last|this past|on) )?` + weekdayPattern + `\b`)
+	openingNightPattern = regexp.MustCompile(`\b(?:opening (?:night|day)|season opener)\b`)
+)
+
+// dateOnDay builds the date for year, month and day, when they make one.
+func dateOnDay(year int, month time.Month, day int) (time.Time, bool) {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if month < time.January || month > time.December || date.Day() != day {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// dateWithoutYear returns the most recent month and day on or before today.
+func dateWithoutYear(today time.Time, month time.Month, day int) (time.Time, bool) {
+	if _, ok := dateOnDay(2024, month, day); !ok {
+		return time.Time{}, false
+	}
+	return mostRecent(today, func(year int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}), true
+}
+
+// extractGameDate finds the first date phrase in text, which must be lower
+// case, and returns the date and text without the phrase. ok is false when
+// text has no date phrase.
+func extractGameDate(text string, now time.Time, calendar SeasonCalendar) (date time.Time, rest string, ok bool, err error) {
+	today := dateOnly(now)
+	remove := func(loc []int) string {
+		return text[:loc[0]] + " " + text[loc[1]:]
+	}
+
+	if m := isoDatePattern.FindStringSubmatchIndex(text); m != nil {
+		year, _ := strconv.Atoi(text[m[2]:m[3]])
+		month, _ := strconv.Atoi(text[m[4]:m[5]])
+		day, _ := strconv.Atoi(text[m[6]:m[7]])
+		if date, ok := dateOnDay(year, time.Month(month), day); ok {
+			return date, remove(m), true, nil
+		}
+	}
+	if m := numericDatePattern.FindStringSubmatchIndex(text); m != nil {
+		month, _ := strconv.Atoi(text[m[2]:m[3]])
+		day, _ := strconv.Atoi(text[m[4]:m[5]])
+		if m[6] >= 0 {
+			year, _ := strconv.Atoi(text[m[6]:m[7]])
+			if year < 100 {
+				year += 2000
+			}
+			if date, ok := dateOnDay(year, time.Month(month), day); ok {
+				return date, remove(m), true, nil
+			}
+		} else if date, ok := dateWithoutYear(today, time.Month(month), day); ok {
+			return date, remove(m), true, nil
+		}
+	}
+	if m := monthDatePattern.FindStringSubmatchIndex(text); m != nil {
+		month := monthsByPrefix[text[m[2]:m[2]+3]]
+		day, _ := strconv.Atoi(text[m[4]:m[5]])
+		if m[6] >= 0 {
+			year, _ := strconv.Atoi(text[m[6]:m[7]])
+			if date, ok := dateOnDay(year, month, day); ok {
+				return date, remove(m), true, nil
+			}
+		} else if date, ok := dateWithoutYear(today, month, day); ok {
+			return date, remove(m), true, nil
+		}
+	}
+	if m := daysAgoPattern.FindStringSubmatchIndex(text); m != nil {
+		days, _ := strconv.Atoi(text[m[2]:m[3]])
+		return today.AddDate(0, 0, -days), remove(m), true, nil
+	}
+	if loc := yesterdayPattern.FindStringIndex(text); loc != nil {
+		return today.AddDate(0, 0, -1), remove(loc), true, nil
+	}
+	if loc := todayPattern.FindStringIndex(text); loc != nil {
+		return today, remove(loc), true, nil
+	}
+	for _, holiday := range holidays {
+		if loc := holiday.pattern.FindStringIndex(text); loc != nil {
+			return mostRecent(today, holiday.date), remove(loc), true, nil
+		}
+	}
+	if loc := openingNightPattern.FindStringIndex(text); loc != nil {
+		if calendar == nil {
+			return time.Time{}, text, true, fmt.Errorf("%w: %s needs the season schedule", ErrUnknownDate, text[loc[0]:loc[1]])
+		}
+		date, err := calendar.OpeningNight(SeasonOf(today))
+		if err != nil {
+			return time.Time{}, text, true, fmt.Errorf("%w: %v", ErrUnknownDate, err)
+		}
+		return date, remove(loc), true, nil
+	}
+	if m := weekdayDatePattern.FindStringSubmatchIndex(text); m != nil {
+		weekday := weekdaysByName[text[m[2]:m[3]]]
+		daysBack := (int(today.Weekday()) - int(weekday) + 7) % 7
+		if daysBack == 0 {
+			daysBack = 7
+		}
+		return today.AddDate(0, 0, -daysBack), remove(m[:2]), true, nil
+	}
+	return time.Time{}, text, false, nil
+}
+
+var queryPunctuation = strings.NewReplacer(".", " ", "!", " ", "?", " ")
+
+// normalizeQuery lower cases text, drops sentence punctuation and collapses
+// its whitespace.
+func normalizeQuery(text string) string {
+	return strings.Join(strings.Fields(queryPunctuation.Replace(strings.ToLower(text))), " ")
+}
+
+// ParseGameDate parses a date phrase such as "yesterday", "last friday",
+// "christmas" or "jan 5". Dates without a year are the most recent one on or
+// before now. calendar may be nil, in which case "opening night" is unknown.
+func ParseGameDate(text string, now time.Time, calendar SeasonCalendar) (time.Time, error) {
+	text = normalizeQuery(text)
+	date, rest, ok, err := extractGameDate(text, now, calendar)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !ok || strings.TrimSpace(rest) != "" {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrUnknownDate, text)
+	}
+	return date, nil
+}
+
+// gameQuerySeparator splits the teams in a query.
+var gameQuerySeparator = regexp.MustCompile(`\s*(?:@|,|&|\b(?:at|vs|versus|v|against|and|or)\b)\s*`)
+
+// gameQueryFillerWords are words around team names that say nothing about
+// the game.
+var gameQueryFillerWords = map[string]bool{
+	"the": true, "game": true, "games": true, "highlights": true, "highlight": true, "recap": true,
+	"on": true, "from": true, "of": true, "for": true, "in": true, "nba": true, "match": true, "matchup": true,
+	"show": true, "me": true, "watch": true, "play": true, "played": true, "last": true, "latest": true,
+}
+
+func stripFillerWords(text string) string {
+	words := []string{}
+	for _, word := range strings.Fields(text) {
+		if !gameQueryFillerWords[word] {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// GameQueryParser turns plain words like "warriors at lakers last night" or
+// "celtics game on christmas" into a GameQuery.
+type GameQueryParser struct {
+	// ResolveTeam finds the team a mention such as "warriors" refers to.
+	ResolveTeam func(mention string) (NBATeam, error)
+	// Calendar looks up season dates like opening night. It may be nil.
+	Calendar SeasonCalendar
+}
+
+// NewGameQueryParser returns a parser that resolves teams with PickTeam.
+func NewGameQueryParser(nbaTeams []NBATeam, calendar SeasonCalendar) *GameQueryParser {
+	return &GameQueryParser{
+		ResolveTeam: func(mention string) (NBATeam, error) {
+			return PickTeam(mention, nbaTeams)
+		},
+		Calendar: calendar,
+	}
+}
+
+// Parse parses query, resolving relative dates against now.
+func (p *GameQueryParser) Parse(query string, now time.Time) (GameQuery, error) {
+	var gameQuery GameQuery
+	text := normalizeQuery(query)
+	date, text, _, err := extractGameDate(text, now, p.Calendar)
+	if err != nil {
+		return gameQuery, err
+	}
+	gameQuery.Date = date
+
+	for _, mention := range gameQuerySeparator.Split(text, -1) {
+		mention = stripFillerWords(mention)
+		if mention == "" {
+			continue
+		}
+		team, err := p.ResolveTeam(mention)
+		if err != nil {
+			return gameQuery, err
+		}
+		gameQuery.Teams = append(gameQuery.Teams, team)
+	}
+	if len(gameQuery.Teams) == 0 {
+		return gameQuery, fmt.Errorf("%w: no team in %q", ErrTeamNotFound, strings.TrimSpace(query))
+	}
+	if len(gameQuery.Teams) > 2 {
+		return gameQuery, fmt.Errorf("Invalid number of teams given")
+	}
+	return gameQuery, nil
+}
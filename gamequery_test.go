@@ -0,0 +1,119 @@
+package hoop_watcher_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+type fakeSeasonCalendar struct {
+	openingNights map[int]time.Time
+}
+
+func (c fakeSeasonCalendar) OpeningNight(season int) (time.Time, error) {
+	date, ok := c.openingNights[season]
+	if !ok {
+		return time.Time{}, errors.New("no games")
+	}
+	return date, nil
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseGameDate(t *testing.T) {
+	// A Sunday.
+	now := time.Date(2026, time.October, 18, 21, 30, 0, 0, time.UTC)
+	calendar := fakeSeasonCalendar{openingNights: map[int]time.Time{2026: day(2026, time.October, 20)}}
+
+	cases := map[string]time.Time{
+		"yesterday":          day(2026, time.October, 17),
+		"last night":         day(2026, time.October, 17),
+		"tonight":            day(2026, time.October, 18),
+		"3 days ago":         day(2026, time.October, 15),
+		"last friday":        day(2026, time.October, 16),
+		"sunday":             day(2026, time.October, 11),
+		"christmas":          day(2025, time.December, 25),
+		"Christmas Day":      day(2025, time.December, 25),
+		"new year's eve":     day(2025, time.December, 31),
+		"mlk day":            day(2026, time.January, 19),
+		"opening night":      day(2026, time.October, 20),
+		"jan 5":              day(2026, time.January, 5),
+		"December 25th 2023": day(2023, time.December, 25),
+		"12/25":              day(2025, time.December, 25),
+		"1/5/24":             day(2024, time.January, 5),
+		"2024-01-05":         day(2024, time.January, 5),
+	}
+	for text, want := range cases {
+		got, err := hoop_watcher.ParseGameDate(text, now, calendar)
+		if err != nil {
+			t.Errorf("got err %v for %q", err, text)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("got %v for %q, want %v", got, text, want)
+		}
+	}
+
+	for _, text := range []string{"someday", "feb 30", "opening night"} {
+		if _, err := hoop_watcher.ParseGameDate(text, now, nil); !errors.Is(err, hoop_watcher.ErrUnknownDate) {
+			t.Errorf("got err %v for %q, want %v", err, text, hoop_watcher.ErrUnknownDate)
+		}
+	}
+}
+
+func TestGameQueryParser(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 21, 30, 0, 0, time.UTC)
+	parser := hoop_watcher.NewGameQueryParser(mustLoadTeams(t), nil)
+
+	t.Run("it parses the visiting then home teams and the date", func(t *testing.T) {
+		got, err := parser.Parse("Warriors at Lakers last night", now)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got.Teams) != 2 || got.Teams[0].Abbreviation != "GSW" || got.Teams[1].Abbreviation != "LAL" {
+			t.Errorf("got teams %v, want the Warriors and the Lakers", got.Teams)
+		}
+		if !got.Date.Equal(day(2026, time.October, 17)) {
+			t.Errorf("got date %v, want October 17", got.Date)
+		}
+	})
+
+	t.Run("it parses a single team and a holiday", func(t *testing.T) {
+		got, err := parser.Parse("celtics game on christmas", now)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got.Teams) != 1 || got.Teams[0].Abbreviation != "BOS" {
+			t.Errorf("got teams %v, want only the Celtics", got.Teams)
+		}
+		if !got.Date.Equal(day(2025, time.December, 25)) {
+			t.Errorf("got date %v, want Christmas 2025", got.Date)
+		}
+	})
+
+	t.Run("it parses teams joined by vs without a date", func(t *testing.T) {
+		got, err := parser.Parse("knicks vs. heat highlights", now)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got.Teams) != 2 || !got.Date.IsZero() {
+			t.Errorf("got %+v, want the Knicks and Heat with no date", got)
+		}
+	})
+
+	t.Run("it returns errors for unknown and ambiguous teams", func(t *testing.T) {
+		if _, err := parser.Parse("supersonics last night", now); !errors.Is(err, hoop_watcher.ErrTeamNotFound) {
+			t.Errorf("got err %v, want %v", err, hoop_watcher.ErrTeamNotFound)
+		}
+		if _, err := parser.Parse("last night", now); !errors.Is(err, hoop_watcher.ErrTeamNotFound) {
+			t.Errorf("got err %v, want %v", err, hoop_watcher.ErrTeamNotFound)
+		}
+		if _, err := parser.Parse("LA yesterday", now); !errors.Is(err, hoop_watcher.ErrAmbiguousTeam) {
+			t.Errorf("got err %v, want %v", err, hoop_watcher.ErrAmbiguousTeam)
+		}
+	})
+}
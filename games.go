@@ -0,0 +1,116 @@
+package hoop_watcher
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+type GameStatus string
+
+const (
+	GameScheduled  GameStatus = "scheduled"
+	GameInProgress GameStatus = "in_progress"
+	GameFinal      GameStatus = "final"
+)
+
+type Game struct {
+	Id        int        `json:"id"`
+	HomeTeam  NBATeam    `json:"home_team"`
+	AwayTeam  NBATeam    `json:"away_team"`
+	Date      time.Time  `json:"date"`
+	TipOff    time.Time  `json:"tip_off"`
+	HomeScore int        `json:"home_score"`
+	AwayScore int        `json:"away_score"`
+	Status    GameStatus `json:"status"`
+}
+
+// Teams returns the away team then the home team, the order highlight titles
+// usually name them in.
+func (g Game) Teams() []NBATeam {
+	return []NBATeam{g.AwayTeam, g.HomeTeam}
+}
+
+// HighlightQuery is the query for the highlights of the game.
+func (g Game) HighlightQuery(kind HighlightKind) HighlightQuery {
+	return HighlightQuery{Teams: g.Teams(), Date: g.Date, Kind: kind}
+}
+
+// rankGameHighlights ranks the highlights cached for the game against its
+// teams, best match first, dropping irrelevant ones and repeats of a video.
+func (g Game) rankGameHighlights(highlights []Highlight) []Highlight {
+	ranked := []Highlight{}
+	seen := map[string]bool{}
+	for _, highlight := range RankHighlights(publishedForGame(highlights, g.Date), g.Teams(), g.Date) {
+		if !isRelevantHighlight(highlight) || seen[highlight.URL.String()] {
+			continue
+		}
+		seen[highlight.URL.String()] = true
+		ranked = append(ranked, highlight)
+	}
+	return ranked
+}
+
+func (g Game) String() string {
+	return fmt.Sprintf("%s @ %s", g.AwayTeam.Abbreviation, g.HomeTeam.Abbreviation)
+}
+
+// GameWithHighlights is a game and the cached highlights linked to it.
+type GameWithHighlights struct {
+	Game
+	Highlights []Highlight `json:"highlights"`
+}
+
+// maxConcurrentSearches bounds how many games' highlights are searched at once.
+const maxConcurrentSearches = 4
+
+// GameHighlights are the highlights found for one game, or the error that
+// occurred searching for them.
+type GameHighlights struct {
+	Game       Game
+	Highlights []Highlight
+	Err        error
+}
+
+// GetHighlightsForGames searches the highlights of every game concurrently,
+// returning the results in the order of games.
+func GetHighlightsForGames(games []Game, kind HighlightKind, provider HighlightProvider) []GameHighlights {
+	results := make([]GameHighlights, len(games))
+	sem := make(chan struct{}, maxConcurrentSearches)
+	var wg sync.WaitGroup
+	for i, game := range games {
+		wg.Add(1)
+		go func(i int, game Game) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			highlights, err := FindHighlights(game.HighlightQuery(kind), provider)
+			results[i] = GameHighlights{Game: game, Highlights: highlights, Err: err}
+		}(i, game)
+	}
+	wg.Wait()
+	return results
+}
+
+// PrintGameHighlights writes one numbered list of highlights per game.
+func PrintGameHighlights(out io.Writer, results []GameHighlights) {
+	for i, result := range results {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "%s (%s vs %s)\n", result.Game, result.Game.AwayTeam.Name, result.Game.HomeTeam.Name)
+		if result.Err != nil {
+			fmt.Fprintf(out, "  Error occurred fetching highlights: %v\n", result.Err)
+			continue
+		}
+		if len(result.Highlights) == 0 {
+			fmt.Fprintln(out, "  No highlights found")
+			continue
+		}
+		for j, highlight := range result.Highlights {
+			fmt.Fprintf(out, "  [%d] %s | %s | %s\n", j+1, highlight.Channel, highlight.Title, highlight.URL.String())
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package hoop_watcher_test
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+func TestSqliteGames(t *testing.T) {
+	knicks := hoop_watcher.NBATeam{Id: 20}
+	heat := hoop_watcher.NBATeam{Id: 16}
+	lakers := hoop_watcher.NBATeam{Id: 14}
+	gameDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("it adds and looks up games", func(t *testing.T) {
+		db := newTestDB(t)
+		tipOff := time.Date(2023, time.January, 2, 0, 30, 0, 0, time.UTC)
+		added, err := db.AddGame(hoop_watcher.Game{HomeTeam: heat, AwayTeam: knicks, Date: gameDate, TipOff: tipOff})
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if added.Id == 0 || added.HomeTeam.FullName != "Miami Heat" || added.AwayTeam.FullName != "New York Knicks" {
+			t.Errorf("got %v, want the Knicks at the Heat", added)
+		}
+		if added.Status != hoop_watcher.GameScheduled || !added.TipOff.Equal(tipOff) || !added.Date.Equal(gameDate) {
+			t.Errorf("got status %s, tip-off %v and date %v", added.Status, added.TipOff, added.Date)
+		}
+
+		if _, err := db.AddGame(hoop_watcher.Game{HomeTeam: lakers, AwayTeam: heat, Date: gameDate.AddDate(0, 0, 2)}); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		byDate, err := db.GetGamesByDate(gameDate)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(byDate) != 1 || byDate[0].Id != added.Id {
+			t.Errorf("got %v, want only the Knicks at the Heat", byDate)
+		}
+
+		forTeam, err := db.GetGamesForTeam(heat.Id)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(forTeam) != 2 || forTeam[0].HomeTeam.Id != lakers.Id {
+			t.Errorf("got %v, want both Heat games with the latest first", forTeam)
+		}
+
+		byTeams, err := db.GetGameByTeamsAndDate(heat.Id, knicks.Id, gameDate)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if byTeams.Id != added.Id {
+			t.Errorf("got %v, want %v", byTeams, added)
+		}
+
+		if _, err := db.GetGameByTeamsAndDate(lakers.Id, knicks.Id, gameDate); err != sql.ErrNoRows {
+			t.Errorf("got err %v, want %v", err, sql.ErrNoRows)
+		}
+	})
+
+	t.Run("it updates the score of an existing game", func(t *testing.T) {
+		db := newTestDB(t)
+		game := hoop_watcher.Game{HomeTeam: heat, AwayTeam: knicks, Date: gameDate}
+		scheduled, err := db.AddGame(game)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		game.HomeScore, game.AwayScore, game.Status = 101, 99, hoop_watcher.GameFinal
+		final, err := db.AddGame(game)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if final.Id != scheduled.Id || final.HomeScore != 101 || final.AwayScore != 99 || final.Status != hoop_watcher.GameFinal {
+			t.Errorf("got %v, want the final score of game %d", final, scheduled.Id)
+		}
+	})
+
+	t.Run("it lists games by team and date range", func(t *testing.T) {
+		db := newTestDB(t)
+		ids := []int{}
+		for i, game := range []hoop_watcher.Game{
+			{HomeTeam: heat, AwayTeam: knicks, Date: gameDate},
+			{HomeTeam: lakers, AwayTeam: heat, Date: gameDate.AddDate(0, 0, 2)},
+			{HomeTeam: knicks, AwayTeam: lakers, Date: gameDate.AddDate(0, 0, 4)},
+		} {
+			added, err := db.AddGame(game)
+			if err != nil {
+				t.Fatalf("Found err adding game %d: %v", i, err)
+			}
+			ids = append(ids, added.Id)
+		}
+
+		cases := map[string]struct {
+			filter hoop_watcher.GameFilter
+			want   []int
+		}{
+			"everything":      {hoop_watcher.GameFilter{}, ids},
+			"a team":          {hoop_watcher.GameFilter{TeamId: knicks.Id}, []int{ids[0], ids[2]}},
+			"a date range":    {hoop_watcher.GameFilter{From: gameDate.AddDate(0, 0, 1), To: gameDate.AddDate(0, 0, 4)}, ids[1:]},
+			"a single date":   {hoop_watcher.GameFilter{From: gameDate, To: gameDate}, ids[:1]},
+			"a page":          {hoop_watcher.GameFilter{Limit: 1, Offset: 1}, ids[1:2]},
+			"past the end":    {hoop_watcher.GameFilter{Offset: 3}, []int{}},
+			"a team and date": {hoop_watcher.GameFilter{TeamId: heat.Id, From: gameDate.AddDate(0, 0, 1)}, ids[1:2]},
+		}
+		for name, c := range cases {
+			games, err := db.ListGames(c.filter)
+			if err != nil {
+				t.Fatalf("Found err listing %s: %v", name, err)
+			}
+			got := []int{}
+			for _, game := range games {
+				got = append(got, game.Id)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got games %v for %s, want %v", got, name, c.want)
+			}
+		}
+	})
+
+	t.Run("it links highlights cached before the game was added", func(t *testing.T) {
+		db := newTestDB(t)
+		game := hoop_watcher.Game{HomeTeam: heat, AwayTeam: knicks, Date: gameDate}
+		highlight := hoop_watcher.Highlight{Title: "Knicks vs Heat Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=abc")}
+		if err := db.CacheHighlights(game.HighlightQuery(hoop_watcher.RecapHighlights), []hoop_watcher.Highlight{highlight}, time.Now()); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		game, err := db.AddGame(game)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		got, err := db.GetGameHighlights(game.Id)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 1 || got[0].URL != highlight.URL {
+			t.Errorf("got %v, want %v", got, []hoop_watcher.Highlight{highlight})
+		}
+	})
+
+	t.Run("it links cached highlights to the game", func(t *testing.T) {
+		db := newTestDB(t)
+		game, err := db.AddGame(hoop_watcher.Game{HomeTeam: heat, AwayTeam: knicks, Date: gameDate})
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		highlight := hoop_watcher.Highlight{Title: "Knicks vs Heat Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=abc")}
+		if err := db.CacheHighlights(game.HighlightQuery(hoop_watcher.AnyHighlights), []hoop_watcher.Highlight{highlight}, time.Now()); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		got, err := db.GetGameHighlights(game.Id)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(got) != 1 || got[0].Title != highlight.Title {
+			t.Errorf("got %v, want %v", got, []hoop_watcher.Highlight{highlight})
+		}
+	})
+}
+
+type teamsHighlightProvider struct {
+	highlightsByTeam map[int][]hoop_watcher.Highlight
+}
+
+func (p *teamsHighlightProvider) SearchHighlights(query hoop_watcher.HighlightQuery) ([]hoop_watcher.Highlight, error) {
+	if len(query.Teams) == 0 {
+		return nil, errors.New("no teams")
+	}
+	return p.highlightsByTeam[query.Teams[0].Id], nil
+}
+
+func TestSqliteOpeningNight(t *testing.T) {
+	db := newTestDB(t)
+	knicks := hoop_watcher.NBATeam{Id: 20}
+	heat := hoop_watcher.NBATeam{Id: 16}
+	for _, date := range []time.Time{
+		time.Date(2023, time.April, 9, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, time.October, 24, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, time.November, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if _, err := db.AddGame(hoop_watcher.Game{HomeTeam: heat, AwayTeam: knicks, Date: date}); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+	}
+
+	got, err := db.OpeningNight(2023)
+	if err != nil {
+		t.Fatalf("Found err: %v", err)
+	}
+	if want := time.Date(2023, time.October, 24, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := db.OpeningNight(2024); err != sql.ErrNoRows {
+		t.Errorf("got err %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestGetHighlightsForGames(t *testing.T) {
+	knicks := hoop_watcher.NBATeam{Id: 20, Name: "Knicks", FullName: "New York Knicks", Abbreviation: "NYK"}
+	heat := hoop_watcher.NBATeam{Id: 16, Name: "Heat", FullName: "Miami Heat", Abbreviation: "MIA"}
+	lakers := hoop_watcher.NBATeam{Id: 14, Name: "Lakers", FullName: "Los Angeles Lakers", Abbreviation: "LAL"}
+	suns := hoop_watcher.NBATeam{Id: 24, Name: "Suns", FullName: "Phoenix Suns", Abbreviation: "PHX"}
+	gameDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	games := []hoop_watcher.Game{
+		{Id: 1, AwayTeam: knicks, HomeTeam: heat, Date: gameDate},
+		{Id: 2, AwayTeam: lakers, HomeTeam: suns, Date: gameDate},
+		{Id: 3, AwayTeam: hoop_watcher.NBATeam{}, HomeTeam: suns, Date: gameDate},
+	}
+	provider := &teamsHighlightProvider{highlightsByTeam: map[int][]hoop_watcher.Highlight{
+		knicks.Id: {
+			{Title: "Knicks vs Heat reaction", URL: mustParseURL(t, "https://www.youtube.com/watch?v=react")},
+			{Title: "Knicks vs Heat Full Game Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=nyk")},
+		},
+		lakers.Id: {
+			{Title: "Lakers vs Suns Full Game Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=lal")},
+		},
+	}}
+
+	got := hoop_watcher.GetHighlightsForGames(games, hoop_watcher.AnyHighlights, provider)
+
+	if len(got) != len(games) {
+		t.Fatalf("got %d results, want %d", len(got), len(games))
+	}
+	for i, result := range got {
+		if result.Game.Id != games[i].Id {
+			t.Errorf("got game %d at position %d, want %d", result.Game.Id, i, games[i].Id)
+		}
+	}
+	if len(got[0].Highlights) != 2 || got[0].Highlights[0].URL.Query().Get("v") != "nyk" {
+		t.Errorf("got %v, want the ranked Knicks highlights", got[0].Highlights)
+	}
+	if len(got[1].Highlights) != 1 || got[1].Highlights[0].URL.Query().Get("v") != "lal" {
+		t.Errorf("got %v, want the Lakers highlight", got[1].Highlights)
+	}
+
+	out := &bytes.Buffer{}
+	hoop_watcher.PrintGameHighlights(out, got)
+	for _, want := range []string{
+		"NYK @ MIA (Knicks vs Heat)\n  [1] ",
+		"LAL @ PHX (Lakers vs Suns)\n  [1] ",
+		"No highlights found",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output %q is missing %q", out.String(), want)
+		}
+	}
+}
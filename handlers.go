@@ -3,22 +3,69 @@ package hoop_watcher
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type BaseHandler struct {
-	db HoopWatcherDB
+	db         HoopWatcherDB
+	highlights HighlightProvider
 }
 
-func NewBaseHandler(db HoopWatcherDB) *BaseHandler {
-	return &BaseHandler{db: db}
+func NewBaseHandler(db HoopWatcherDB, highlights HighlightProvider) *BaseHandler {
+	return &BaseHandler{db: db, highlights: highlights}
+}
+
+// Routes maps the API's ServeMux patterns to their handlers. Every route is
+// described in openapi.json.
+func (h *BaseHandler) Routes() map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"GET /{$}":          h.GetRoot,
+		"GET /openapi.json": h.GetOpenAPISpec,
+		"GET /docs":         h.GetDocs,
+
+		"GET /teams":                      h.GetTeams,
+		"GET /teams/{abbrev}":             h.GetTeam,
+		"GET /teams/{abbrev}/highlights":  h.GetTeamHighlights,
+		"GET /teams/{abbrev}/games":       h.GetTeamGames,
+		"GET /teams/favorites":            h.GetFavoriteTeams,
+		"GET /teams/search":               h.SearchTeams,
+		"PUT /teams/{abbrev}/favorite":    h.PutTeamFavorite,
+		"DELETE /teams/{abbrev}/favorite": h.DeleteTeamFavorite,
+
+		"GET /games":      h.GetGames,
+		"GET /games/{id}": h.GetGame,
+	}
+}
+
+// RegisterRoutes adds the API's routes to router, validating the parameters
+// of each request against DefaultOpenAPISpec.
+func (h *BaseHandler) RegisterRoutes(router *http.ServeMux) {
+	for pattern, handler := range h.Routes() {
+		router.HandleFunc(pattern, validateRequests(DefaultOpenAPISpec.Operation(pattern), handler))
+	}
 }
 
 func handleDBError(w http.ResponseWriter, err error) {
-	if err == sql.ErrNoRows {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, ErrTeamNotFound):
 		http.Error(w, "No results found", http.StatusNotFound)
 		return
+	case errors.Is(err, ErrQuotaExceeded):
+		log.Printf("Error occurred searching highlights: %v", err)
+		retryAfter := youtubeQuotaReset(time.Now()).Sub(time.Now()).Round(time.Second)
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+		http.Error(w, "Highlight search quota exceeded", http.StatusTooManyRequests)
+		return
+	case errors.Is(err, ErrProviderUnavailable):
+		log.Printf("Error occurred searching highlights: %v", err)
+		http.Error(w, "Highlights Unavailable", http.StatusServiceUnavailable)
+		return
 	}
 	log.Printf("Unknown error occurred: %v", err)
 	http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -29,6 +76,12 @@ func writeJSON(w http.ResponseWriter, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
+// lookupTeam resolves the {abbrev} path value, which may also be a team's
+// name or alias.
+func (h *BaseHandler) lookupTeam(r *http.Request) (NBATeam, error) {
+	return ResolveTeam(h.db, r.PathValue("abbrev"))
+}
+
 func (h *BaseHandler) GetRoot(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, map[string]string{"status": "ok"})
 }
@@ -42,10 +95,30 @@ func (h *BaseHandler) GetTeams(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, teams)
 }
 
+// SearchTeams ranks the teams the q query parameter could refer to, best
+// match first.
+func (h *BaseHandler) SearchTeams(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if strings.TrimSpace(query) == "" {
+		writeBadRequest(w, missingParameter("q", "query"))
+		return
+	}
+	teams, err := h.db.GetAllTeams()
+	if err != nil {
+		handleDBError(w, err)
+		return
+	}
+	candidates := []TeamCandidate{}
+	for _, candidate := range RankTeams(query, teams) {
+		if candidate.Score >= TeamMatchThreshold {
+			candidates = append(candidates, candidate)
+		}
+	}
+	writeJSON(w, candidates)
+}
+
 func (h *BaseHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
-	abbrev := r.PathValue("abbrev")
-	log.Printf("abbrev: %s", abbrev)
-	team, err := h.db.GetTeamByAbbrev(abbrev)
+	team, err := h.lookupTeam(r)
 	if err != nil {
 		handleDBError(w, err)
 		return
@@ -53,24 +126,244 @@ func (h *BaseHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, team)
 }
 
+// highlightQuery builds the highlight query of a GetTeamHighlights request.
+// Without a date query parameter, it queries the team's most recent game like
+// a zero HighlightQuery date does. Without an opponent query parameter, the
+// opponent is taken from the team's game on the date, if there was one.
+func (h *BaseHandler) highlightQuery(r *http.Request, team NBATeam) (query HighlightQuery, limit int, err error) {
+	params := r.URL.Query()
+	gameDate, err := parseDateParam(params, "date")
+	if err != nil {
+		return query, 0, err
+	}
+	kind, err := ParseHighlightKind(params.Get("kind"))
+	if err != nil {
+		return query, 0, invalidParameter("kind", "query")
+	}
+	if limitStr := params.Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return query, 0, invalidParameter("limit", "query")
+		}
+	}
+
+	query = HighlightQuery{Teams: []NBATeam{team}, Date: gameDate, Kind: kind}
+	if opponentStr := params.Get("opponent"); opponentStr != "" {
+		opponent, err := ResolveTeam(h.db, opponentStr)
+		if err != nil || opponent.Id == team.Id {
+			return query, 0, invalidParameter("opponent", "query")
+		}
+		query.Teams = append(query.Teams, opponent)
+		return query, limit, nil
+	}
+	if gameDate.IsZero() {
+		return query, limit, nil
+	}
+	games, err := h.db.GetGamesByDate(gameDate)
+	if err != nil {
+		log.Printf("Error occurred loading games: %v", err)
+		return query, limit, nil
+	}
+	for _, game := range games {
+		if game.HomeTeam.Id == team.Id || game.AwayTeam.Id == team.Id {
+			query.Teams = game.Teams()
+			break
+		}
+	}
+	return query, limit, nil
+}
+
+// GetTeamHighlights finds the highlights of the team's game on the date query
+// parameter, or its most recent game, best match first. Results come from the highlight provider,
+// which the server backs with the SQLite cache.
 func (h *BaseHandler) GetTeamHighlights(w http.ResponseWriter, r *http.Request) {
-	abbrev := r.PathValue("abbrev")
-	team, err := h.db.GetTeamByAbbrev(abbrev)
+	team, err := h.lookupTeam(r)
 	if err != nil {
 		handleDBError(w, err)
 		return
 	}
 
-	date := r.URL.Query().Get("date")
-	if date == "" {
-		http.Error(w, "Missing date query parameter", http.StatusBadRequest)
+	query, limit, err := h.highlightQuery(r, team)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if h.highlights == nil {
+		handleDBError(w, ErrProviderUnavailable)
 		return
 	}
 
-	highlights, err := h.db.GetTeamHighlights(team.Id)
+	highlights, err := FindHighlights(query, h.highlights)
 	if err != nil {
 		handleDBError(w, err)
 		return
 	}
+	if limit > 0 && len(highlights) > limit {
+		highlights = highlights[:limit]
+	}
 	writeJSON(w, highlights)
 }
+
+// Page sizes of the game listings.
+const (
+	DefaultGamesLimit = 25
+	MaxGamesLimit     = 100
+)
+
+// parseDateParam parses the date query parameter name, returning a zero time
+// when it is missing.
+func parseDateParam(params url.Values, name string) (time.Time, error) {
+	value := params.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(DAILY_DATE_FORMAT, value)
+	if err != nil {
+		return time.Time{}, invalidParameter(name, "query")
+	}
+	return date, nil
+}
+
+// gameFilter builds the filter of a game listing from its date, from, to,
+// limit and offset query parameters. date is short for the same from and to.
+func gameFilter(r *http.Request) (filter GameFilter, err error) {
+	params := r.URL.Query()
+	date, err := parseDateParam(params, "date")
+	if err != nil {
+		return filter, err
+	}
+	if filter.From, err = parseDateParam(params, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseDateParam(params, "to"); err != nil {
+		return filter, err
+	}
+	if !date.IsZero() {
+		if !filter.From.IsZero() || !filter.To.IsZero() {
+			return filter, &ParameterError{Message: "Invalid date query parameter, use either date or from and to", Parameter: "date", In: "query"}
+		}
+		filter.From, filter.To = date, date
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return filter, &ParameterError{Message: "Invalid to query parameter, it is before from", Parameter: "to", In: "query"}
+	}
+
+	filter.Limit = DefaultGamesLimit
+	if limitStr := params.Get("limit"); limitStr != "" {
+		filter.Limit, err = strconv.Atoi(limitStr)
+		if err != nil || filter.Limit < 1 || filter.Limit > MaxGamesLimit {
+			return filter, invalidParameter("limit", "query")
+		}
+	}
+	if offsetStr := params.Get("offset"); offsetStr != "" {
+		filter.Offset, err = strconv.Atoi(offsetStr)
+		if err != nil || filter.Offset < 0 {
+			return filter, invalidParameter("offset", "query")
+		}
+	}
+	return filter, nil
+}
+
+// withHighlights links each game to its cached highlights, ranked for the game.
+func (h *BaseHandler) withHighlights(games []Game) ([]GameWithHighlights, error) {
+	results := []GameWithHighlights{}
+	for _, game := range games {
+		highlights, err := h.db.GetGameHighlights(game.Id)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, GameWithHighlights{Game: game, Highlights: game.rankGameHighlights(highlights)})
+	}
+	return results, nil
+}
+
+// writeGames responds with the games matching filter and their highlights.
+func (h *BaseHandler) writeGames(w http.ResponseWriter, filter GameFilter) {
+	games, err := h.db.ListGames(filter)
+	if err != nil {
+		handleDBError(w, err)
+		return
+	}
+	results, err := h.withHighlights(games)
+	if err != nil {
+		handleDBError(w, err)
+		return
+	}
+	writeJSON(w, results)
+}
+
+// GetGames lists the games between the from and to query parameters, or on
+// date, earliest first. Pages are limit games long, starting after offset.
+func (h *BaseHandler) GetGames(w http.ResponseWriter, r *http.Request) {
+	filter, err := gameFilter(r)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	h.writeGames(w, filter)
+}
+
+// GetTeamGames lists the team's games, filtered and paged like GetGames.
+func (h *BaseHandler) GetTeamGames(w http.ResponseWriter, r *http.Request) {
+	team, err := h.lookupTeam(r)
+	if err != nil {
+		handleDBError(w, err)
+		return
+	}
+	filter, err := gameFilter(r)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	filter.TeamId = team.Id
+	h.writeGames(w, filter)
+}
+
+func (h *BaseHandler) GetGame(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeBadRequest(w, invalidParameter("id", "path"))
+		return
+	}
+	game, err := h.db.GetGame(id)
+	if err != nil {
+		handleDBError(w, err)
+		return
+	}
+	results, err := h.withHighlights([]Game{game})
+	if err != nil {
+		handleDBError(w, err)
+		return
+	}
+	writeJSON(w, results[0])
+}
+
+func (h *BaseHandler) GetFavoriteTeams(w http.ResponseWriter, r *http.Request) {
+	teams, err := h.db.GetFavoriteTeams()
+	if err != nil {
+		handleDBError(w, err)
+		return
+	}
+	writeJSON(w, teams)
+}
+
+func (h *BaseHandler) setTeamFavorite(w http.ResponseWriter, r *http.Request, favorite bool) {
+	team, err := h.lookupTeam(r)
+	if err != nil {
+		handleDBError(w, err)
+		return
+	}
+	if err := h.db.SetTeamFavorite(team.Id, favorite); err != nil {
+		handleDBError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BaseHandler) PutTeamFavorite(w http.ResponseWriter, r *http.Request) {
+	h.setTeamFavorite(w, r, true)
+}
+
+func (h *BaseHandler) DeleteTeamFavorite(w http.ResponseWriter, r *http.Request) {
+	h.setTeamFavorite(w, r, false)
+}
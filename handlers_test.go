@@ -4,13 +4,18 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"testing"
+	"time"
 )
 
 func TestGetTeams(t *testing.T) {
@@ -32,7 +37,7 @@ func TestGetTeams(t *testing.T) {
 		}
 		req, _ := http.NewRequest("GET", "/teams", nil)
 		rr := httptest.NewRecorder()
-		h := NewBaseHandler(db)
+		h := NewBaseHandler(db, nil)
 		h.GetTeams(rr, req)
 
 		var got []NBATeam
@@ -61,7 +66,7 @@ func TestGetTeams(t *testing.T) {
 		}
 		req, _ := http.NewRequest("GET", "/teams", nil)
 		rr := httptest.NewRecorder()
-		h := NewBaseHandler(db)
+		h := NewBaseHandler(db, nil)
 		h.GetTeams(rr, req)
 
 		if rr.Code != http.StatusNotFound {
@@ -85,7 +90,7 @@ func TestGetTeams(t *testing.T) {
 		}
 		req, _ := http.NewRequest("GET", "/teams", nil)
 		rr := httptest.NewRecorder()
-		h := NewBaseHandler(db)
+		h := NewBaseHandler(db, nil)
 		h.GetTeams(rr, req)
 
 		if rr.Code != http.StatusInternalServerError {
@@ -98,27 +103,372 @@ func TestGetTeams(t *testing.T) {
 	})
 }
 
+func TestTeamFavorite(t *testing.T) {
+	t.Run("marks and unmarks a team as a favorite", func(t *testing.T) {
+		db := newMockDB()
+		db.getTeamByAbbrev = func(abbrev string) (NBATeam, error) {
+			return NBATeam{Id: 20, Abbreviation: abbrev}, nil
+		}
+		favorites := map[int]bool{}
+		db.setTeamFavorite = func(id int, fav bool) error {
+			favorites[id] = fav
+			return nil
+		}
+		h := NewBaseHandler(db, nil)
+
+		req, _ := http.NewRequest("PUT", "/teams/NYK/favorite", nil)
+		req.SetPathValue("abbrev", "NYK")
+		rr := httptest.NewRecorder()
+		h.PutTeamFavorite(rr, req)
+		if rr.Code != http.StatusNoContent || !favorites[20] {
+			t.Errorf("got %d and favorites %v, want %d and team 20 favorited", rr.Code, favorites, http.StatusNoContent)
+		}
+
+		req, _ = http.NewRequest("DELETE", "/teams/NYK/favorite", nil)
+		req.SetPathValue("abbrev", "NYK")
+		rr = httptest.NewRecorder()
+		h.DeleteTeamFavorite(rr, req)
+		if rr.Code != http.StatusNoContent || favorites[20] {
+			t.Errorf("got %d and favorites %v, want %d and team 20 unfavorited", rr.Code, favorites, http.StatusNoContent)
+		}
+	})
+
+	t.Run("404 if the team does not exist", func(t *testing.T) {
+		db := newMockDB()
+		db.getTeamByAbbrev = func(abbrev string) (NBATeam, error) {
+			return NBATeam{}, sql.ErrNoRows
+		}
+		req, _ := http.NewRequest("PUT", "/teams/SEA/favorite", nil)
+		req.SetPathValue("abbrev", "SEA")
+		rr := httptest.NewRecorder()
+		NewBaseHandler(db, nil).PutTeamFavorite(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("got %d, want %d", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("lists favorite teams", func(t *testing.T) {
+		db := newMockDB()
+		db.getFavoriteTeams = func() ([]NBATeam, error) {
+			return []NBATeam{{Id: 20, Abbreviation: "NYK"}}, nil
+		}
+		req, _ := http.NewRequest("GET", "/teams/favorites", nil)
+		rr := httptest.NewRecorder()
+		NewBaseHandler(db, nil).GetFavoriteTeams(rr, req)
+
+		var got []NBATeam
+		json.Unmarshal(rr.Body.Bytes(), &got)
+		want := []NBATeam{{Id: 20, Abbreviation: "NYK"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func TestSearchTeams(t *testing.T) {
+	db := newMockDB()
+	db.getAllTeams = func() ([]NBATeam, error) {
+		return []NBATeam{
+			{Id: 13, Name: "Clippers", FullName: "LA Clippers", Abbreviation: "LAC", City: "LA"},
+			{Id: 14, Name: "Lakers", FullName: "Los Angeles Lakers", Abbreviation: "LAL", City: "Los Angeles"},
+			{Id: 20, Name: "Knicks", FullName: "New York Knicks", Abbreviation: "NYK", City: "New York"},
+		}, nil
+	}
+
+	t.Run("ranks the teams matching q", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/teams/search?q=LA", nil)
+		rr := httptest.NewRecorder()
+		NewBaseHandler(db, nil).SearchTeams(rr, req)
+
+		var got []TeamCandidate
+		json.Unmarshal(rr.Body.Bytes(), &got)
+		if len(got) != 2 || got[0].Team.Abbreviation != "LAC" || got[1].Team.Abbreviation != "LAL" {
+			t.Errorf("got %v, want the Clippers then the Lakers", got)
+		}
+		if got[0].Score < got[1].Score {
+			t.Errorf("got scores %v and %v, want them in descending order", got[0].Score, got[1].Score)
+		}
+	})
+
+	t.Run("400 if q is missing", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/teams/search", nil)
+		rr := httptest.NewRecorder()
+		NewBaseHandler(db, nil).SearchTeams(rr, req)
+
+		want := ParameterError{Message: "Missing q query parameter", Parameter: "q", In: "query"}
+		if got := parameterError(t, rr); rr.Code != http.StatusBadRequest || got != want {
+			t.Errorf("got %d %v, want %d %v", rr.Code, got, http.StatusBadRequest, want)
+		}
+	})
+}
+
+// parameterError decodes the body of a 400 response.
+func parameterError(t *testing.T, rr *httptest.ResponseRecorder) ParameterError {
+	t.Helper()
+	var body ParameterError
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("could not decode the 400 body %q: %v", rr.Body.String(), err)
+	}
+	return body
+}
+
+func TestGetTeamHighlights(t *testing.T) {
+	t.Run("404 if the team does not exist", func(t *testing.T) {
+		db := newMockDB()
+		db.getTeamByAbbrev = func(abbrev string) (NBATeam, error) {
+			return NBATeam{}, ErrTeamNotFound
+		}
+		req, _ := http.NewRequest("GET", "/teams/SEA/highlights?date=2023-01-01", nil)
+		req.SetPathValue("abbrev", "SEA")
+		rr := httptest.NewRecorder()
+		NewBaseHandler(db, nil).GetTeamHighlights(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("got %d, want %d", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("503 if the highlight provider fails, 429 if it is out of quota", func(t *testing.T) {
+		for providerErr, want := range map[error]int{ErrQuotaExceeded: http.StatusTooManyRequests, ErrProviderUnavailable: http.StatusServiceUnavailable} {
+			provider := &mockHighlightProvider{
+				searchHighlights: func(query HighlightQuery) ([]Highlight, error) {
+					return nil, fmt.Errorf("%w: search failed", providerErr)
+				},
+			}
+			req, _ := http.NewRequest("GET", "/teams/NYK/highlights?date=2023-01-01", nil)
+			req.SetPathValue("abbrev", "NYK")
+			rr := httptest.NewRecorder()
+			NewBaseHandler(newMockDB(), provider).GetTeamHighlights(rr, req)
+
+			if rr.Code != want {
+				t.Errorf("got %d for %v, want %d", rr.Code, providerErr, want)
+			}
+		}
+	})
+
+	t.Run("429 says when the quota resets", func(t *testing.T) {
+		provider := &mockHighlightProvider{
+			searchHighlights: func(query HighlightQuery) ([]Highlight, error) {
+				return nil, ErrQuotaExceeded
+			},
+		}
+		req, _ := http.NewRequest("GET", "/teams/NYK/highlights?date=2023-01-01", nil)
+		req.SetPathValue("abbrev", "NYK")
+		rr := httptest.NewRecorder()
+		NewBaseHandler(newMockDB(), provider).GetTeamHighlights(rr, req)
+
+		retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+		if err != nil || retryAfter <= 0 || retryAfter > 25*60*60 {
+			t.Errorf("got Retry-After %q, want the seconds until the next day", rr.Header().Get("Retry-After"))
+		}
+	})
+
+	knicks := NBATeam{Id: 20, Name: "Knicks", FullName: "New York Knicks", Abbreviation: "NYK"}
+	heat := NBATeam{Id: 16, Name: "Heat", FullName: "Miami Heat", Abbreviation: "MIA"}
+	teamsDB := func() *mockHoopWatcherDB {
+		db := newMockDB()
+		db.getTeamByAbbrev = func(abbrev string) (NBATeam, error) {
+			for _, team := range []NBATeam{knicks, heat} {
+				if team.Abbreviation == abbrev {
+					return team, nil
+				}
+			}
+			return NBATeam{}, ErrTeamNotFound
+		}
+		return db
+	}
+	gameDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	highlights := []Highlight{
+		{Title: "Knicks press conference", URL: url.URL{Scheme: "https", Host: "youtube.com", Path: "/a"}},
+		{Title: "Knicks vs Heat Full Game Highlights", URL: url.URL{Scheme: "https", Host: "youtube.com", Path: "/b"}},
+		{Title: "Knicks vs Heat Highlights", URL: url.URL{Scheme: "https", Host: "youtube.com", Path: "/c"}},
+	}
+
+	t.Run("searches the game against the opponent on the date", func(t *testing.T) {
+		var gotQuery HighlightQuery
+		provider := &mockHighlightProvider{
+			searchHighlights: func(query HighlightQuery) ([]Highlight, error) {
+				gotQuery = query
+				return highlights, nil
+			},
+		}
+		req, _ := http.NewRequest("GET", "/teams/NYK/highlights?date=2023-01-01&opponent=MIA&limit=2", nil)
+		req.SetPathValue("abbrev", "NYK")
+		rr := httptest.NewRecorder()
+		NewBaseHandler(teamsDB(), provider).GetTeamHighlights(rr, req)
+
+		if !reflect.DeepEqual(gotQuery.Teams, []NBATeam{knicks, heat}) || !gotQuery.Date.Equal(gameDate) {
+			t.Errorf("got query %+v, want the Knicks and Heat on %v", gotQuery, gameDate)
+		}
+		var got []Highlight
+		json.Unmarshal(rr.Body.Bytes(), &got)
+		if len(got) != 2 || got[0].Title != "Knicks vs Heat Full Game Highlights" || got[0].Score == 0 {
+			t.Errorf("got %v, want the 2 best scored highlights", got)
+		}
+	})
+
+	t.Run("takes the opponent from the scheduled game", func(t *testing.T) {
+		var gotQuery HighlightQuery
+		provider := &mockHighlightProvider{
+			searchHighlights: func(query HighlightQuery) ([]Highlight, error) {
+				gotQuery = query
+				return highlights, nil
+			},
+		}
+		db := teamsDB()
+		db.getGamesByDate = func(date time.Time) ([]Game, error) {
+			return []Game{{HomeTeam: heat, AwayTeam: knicks, Date: date}}, nil
+		}
+		req, _ := http.NewRequest("GET", "/teams/NYK/highlights?date=2023-01-01", nil)
+		req.SetPathValue("abbrev", "NYK")
+		rr := httptest.NewRecorder()
+		NewBaseHandler(db, provider).GetTeamHighlights(rr, req)
+
+		if !reflect.DeepEqual(gotQuery.Teams, []NBATeam{knicks, heat}) {
+			t.Errorf("got teams %v, want the Knicks and Heat", gotQuery.Teams)
+		}
+	})
+
+	t.Run("searches the most recent game without a date", func(t *testing.T) {
+		var gotQuery HighlightQuery
+		provider := &mockHighlightProvider{
+			searchHighlights: func(query HighlightQuery) ([]Highlight, error) {
+				gotQuery = query
+				return highlights, nil
+			},
+		}
+		req, _ := http.NewRequest("GET", "/teams/NYK/highlights?opponent=MIA", nil)
+		req.SetPathValue("abbrev", "NYK")
+		rr := httptest.NewRecorder()
+		NewBaseHandler(teamsDB(), provider).GetTeamHighlights(rr, req)
+
+		if rr.Code != http.StatusOK || !gotQuery.Date.IsZero() || !reflect.DeepEqual(gotQuery.Teams, []NBATeam{knicks, heat}) {
+			t.Errorf("got %d and query %v, want the Knicks and Heat without a date", rr.Code, gotQuery)
+		}
+	})
+
+	t.Run("400 on invalid query parameters", func(t *testing.T) {
+		provider := &mockHighlightProvider{
+			searchHighlights: func(query HighlightQuery) ([]Highlight, error) {
+				return highlights, nil
+			},
+		}
+		cases := map[string]string{
+			"?date=01-01-2023":              "Invalid date query parameter",
+			"?date=2023-01-01&kind=x":       "Invalid kind query parameter",
+			"?date=2023-01-01&limit=0":      "Invalid limit query parameter",
+			"?date=2023-01-01&limit=x":      "Invalid limit query parameter",
+			"?date=2023-01-01&opponent=SEA": "Invalid opponent query parameter",
+			"?date=2023-01-01&opponent=NYK": "Invalid opponent query parameter",
+		}
+		for params, want := range cases {
+			req, _ := http.NewRequest("GET", "/teams/NYK/highlights"+params, nil)
+			req.SetPathValue("abbrev", "NYK")
+			rr := httptest.NewRecorder()
+			NewBaseHandler(teamsDB(), provider).GetTeamHighlights(rr, req)
+
+			if got := parameterError(t, rr); rr.Code != http.StatusBadRequest || got.Message != want {
+				t.Errorf("got %d %q for %q, want %d %q", rr.Code, got.Message, params, http.StatusBadRequest, want)
+			}
+		}
+	})
+
+	t.Run("serves repeat requests from the SQLite cache", func(t *testing.T) {
+		db, err := NewSqliteHoopWatcherDB(filepath.Join(t.TempDir(), "hoop-watcher-test.db"))
+		if err != nil {
+			t.Fatalf("could not create test db: %v", err)
+		}
+		defer db.Close()
+		if err := db.InitData("./" + TeamFileName); err != nil {
+			t.Fatalf("could not init test db: %v", err)
+		}
+		searches := 0
+		provider := &mockHighlightProvider{
+			searchHighlights: func(query HighlightQuery) ([]Highlight, error) {
+				searches++
+				return highlights, nil
+			},
+		}
+		h := NewBaseHandler(db, NewCachedHighlightProvider(provider, db, time.Hour))
+
+		for i := 0; i < 2; i++ {
+			req, _ := http.NewRequest("GET", "/teams/nyk/highlights?date=2023-01-01&opponent=heat", nil)
+			req.SetPathValue("abbrev", "nyk")
+			rr := httptest.NewRecorder()
+			h.GetTeamHighlights(rr, req)
+
+			var got []Highlight
+			json.Unmarshal(rr.Body.Bytes(), &got)
+			if rr.Code != http.StatusOK || len(got) != 3 {
+				t.Fatalf("got %d and %v, want all 3 highlights", rr.Code, got)
+			}
+		}
+		if searches != 1 {
+			t.Errorf("got %d searches, want 1", searches)
+		}
+	})
+}
+
+type mockHighlightProvider struct {
+	searchHighlights func(query HighlightQuery) ([]Highlight, error)
+}
+
+func (m *mockHighlightProvider) SearchHighlights(query HighlightQuery) ([]Highlight, error) {
+	return m.searchHighlights(query)
+}
+
 type mockHoopWatcherDB struct {
 	getAllTeams       func() ([]NBATeam, error)
+	getTeamByID       func(id int) (NBATeam, error)
 	getTeamByAbbrev   func(abbrev string) (NBATeam, error)
+	getTeamByName     func(name string) (NBATeam, error)
 	setTeamFavorite   func(id int, fav bool) error
-	getTeamHighlights func(id int) ([]Highlight, error)
+	getFavoriteTeams  func() ([]NBATeam, error)
+	getGamesByDate    func(date time.Time) ([]Game, error)
+	getGame           func(id int) (Game, error)
+	listGames         func(filter GameFilter) ([]Game, error)
+	getGameHighlights func(gameId int) ([]Highlight, error)
 }
 
 func (m *mockHoopWatcherDB) GetAllTeams() ([]NBATeam, error) {
 	return m.getAllTeams()
 }
 
+func (m *mockHoopWatcherDB) GetTeamByID(id int) (NBATeam, error) {
+	return m.getTeamByID(id)
+}
+
 func (m *mockHoopWatcherDB) GetTeamByAbbrev(abbrev string) (NBATeam, error) {
 	return m.getTeamByAbbrev(abbrev)
 }
 
+func (m *mockHoopWatcherDB) GetTeamByName(name string) (NBATeam, error) {
+	return m.getTeamByName(name)
+}
+
 func (m *mockHoopWatcherDB) SetTeamFavorite(id int, fav bool) error {
 	return m.setTeamFavorite(id, fav)
 }
 
-func (m *mockHoopWatcherDB) GetTeamHighlights(id int) ([]Highlight, error) {
-	return m.getTeamHighlights(id)
+func (m *mockHoopWatcherDB) GetFavoriteTeams() ([]NBATeam, error) {
+	return m.getFavoriteTeams()
+}
+
+func (m *mockHoopWatcherDB) GetGamesByDate(date time.Time) ([]Game, error) {
+	return m.getGamesByDate(date)
+}
+
+func (m *mockHoopWatcherDB) GetGame(id int) (Game, error) {
+	return m.getGame(id)
+}
+
+func (m *mockHoopWatcherDB) ListGames(filter GameFilter) ([]Game, error) {
+	return m.listGames(filter)
+}
+
+func (m *mockHoopWatcherDB) GetGameHighlights(gameId int) ([]Highlight, error) {
+	return m.getGameHighlights(gameId)
 }
 
 func newMockDB() *mockHoopWatcherDB {
@@ -126,11 +476,214 @@ func newMockDB() *mockHoopWatcherDB {
 		getAllTeams: func() ([]NBATeam, error) {
 			return []NBATeam{}, nil
 		},
+		getTeamByID: func(id int) (NBATeam, error) {
+			return NBATeam{}, ErrTeamNotFound
+		},
 		getTeamByAbbrev: func(abbrev string) (NBATeam, error) {
 			return NBATeam{}, nil
 		},
-		getTeamHighlights: func(id int) ([]Highlight, error) {
+		getTeamByName: func(name string) (NBATeam, error) {
+			return NBATeam{}, ErrTeamNotFound
+		},
+		setTeamFavorite: func(id int, fav bool) error {
+			return nil
+		},
+		getFavoriteTeams: func() ([]NBATeam, error) {
+			return []NBATeam{}, nil
+		},
+		getGamesByDate: func(date time.Time) ([]Game, error) {
+			return []Game{}, nil
+		},
+		getGame: func(id int) (Game, error) {
+			return Game{}, sql.ErrNoRows
+		},
+		listGames: func(filter GameFilter) ([]Game, error) {
+			return []Game{}, nil
+		},
+		getGameHighlights: func(gameId int) ([]Highlight, error) {
 			return []Highlight{}, nil
 		},
 	}
 }
+
+func TestGetGames(t *testing.T) {
+	knicks := NBATeam{Id: 20, Name: "Knicks", FullName: "New York Knicks", Abbreviation: "NYK"}
+	heat := NBATeam{Id: 16, Name: "Heat", FullName: "Miami Heat", Abbreviation: "MIA"}
+	date := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	game := Game{Id: 1, HomeTeam: heat, AwayTeam: knicks, Date: date}
+	highlight := Highlight{Title: "Knicks vs Heat Highlights", URL: url.URL{Scheme: "https", Host: "www.youtube.com", Path: "/watch", RawQuery: "v=abc"}}
+
+	gamesDB := func(gotFilter *GameFilter) *mockHoopWatcherDB {
+		db := newMockDB()
+		db.getTeamByAbbrev = func(abbrev string) (NBATeam, error) {
+			if abbrev == "NYK" {
+				return knicks, nil
+			}
+			return NBATeam{}, ErrTeamNotFound
+		}
+		db.listGames = func(filter GameFilter) ([]Game, error) {
+			*gotFilter = filter
+			return []Game{game}, nil
+		}
+		db.getGameHighlights = func(gameId int) ([]Highlight, error) {
+			return []Highlight{highlight}, nil
+		}
+		return db
+	}
+
+	t.Run("lists games with their highlights", func(t *testing.T) {
+		var gotFilter GameFilter
+		req, _ := http.NewRequest("GET", "/games?date=2023-01-01", nil)
+		rr := httptest.NewRecorder()
+		NewBaseHandler(gamesDB(&gotFilter), nil).GetGames(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", rr.Code, http.StatusOK)
+		}
+		var got []GameWithHighlights
+		json.NewDecoder(rr.Body).Decode(&got)
+		if len(got) != 1 || got[0].Id != game.Id || len(got[0].Highlights) != 1 || got[0].Highlights[0].URL != highlight.URL {
+			t.Errorf("got %v, want %v with %v", got, game, highlight)
+		}
+		if want := (GameFilter{From: date, To: date, Limit: DefaultGamesLimit}); !reflect.DeepEqual(gotFilter, want) {
+			t.Errorf("got filter %v, want %v", gotFilter, want)
+		}
+	})
+
+	t.Run("filters and pages with from, to, limit and offset", func(t *testing.T) {
+		var gotFilter GameFilter
+		req, _ := http.NewRequest("GET", "/games?from=2023-01-01&to=2023-01-31&limit=10&offset=20", nil)
+		rr := httptest.NewRecorder()
+		NewBaseHandler(gamesDB(&gotFilter), nil).GetGames(rr, req)
+
+		want := GameFilter{From: date, To: date.AddDate(0, 0, 30), Limit: 10, Offset: 20}
+		if rr.Code != http.StatusOK || !reflect.DeepEqual(gotFilter, want) {
+			t.Errorf("got %d with filter %v, want %d with %v", rr.Code, gotFilter, http.StatusOK, want)
+		}
+	})
+
+	t.Run("lists a team's games", func(t *testing.T) {
+		var gotFilter GameFilter
+		req, _ := http.NewRequest("GET", "/teams/NYK/games?from=2023-01-01", nil)
+		req.SetPathValue("abbrev", "NYK")
+		rr := httptest.NewRecorder()
+		NewBaseHandler(gamesDB(&gotFilter), nil).GetTeamGames(rr, req)
+
+		want := GameFilter{TeamId: knicks.Id, From: date, Limit: DefaultGamesLimit}
+		if rr.Code != http.StatusOK || !reflect.DeepEqual(gotFilter, want) {
+			t.Errorf("got %d with filter %v, want %d with %v", rr.Code, gotFilter, http.StatusOK, want)
+		}
+
+		req, _ = http.NewRequest("GET", "/teams/SEA/games", nil)
+		req.SetPathValue("abbrev", "SEA")
+		rr = httptest.NewRecorder()
+		NewBaseHandler(gamesDB(&gotFilter), nil).GetTeamGames(rr, req)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("got status %d, want %d", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("400 on invalid query parameters", func(t *testing.T) {
+		cases := map[string]string{
+			"?date=01-01-2023":               "Invalid date query parameter",
+			"?from=2023-01-32":               "Invalid from query parameter",
+			"?date=2023-01-01&to=2023-01-02": "Invalid date query parameter, use either date or from and to",
+			"?from=2023-01-02&to=2023-01-01": "Invalid to query parameter, it is before from",
+			"?limit=0":                       "Invalid limit query parameter",
+			"?limit=101":                     "Invalid limit query parameter",
+			"?offset=-1":                     "Invalid offset query parameter",
+		}
+		for params, want := range cases {
+			var gotFilter GameFilter
+			req, _ := http.NewRequest("GET", "/games"+params, nil)
+			rr := httptest.NewRecorder()
+			NewBaseHandler(gamesDB(&gotFilter), nil).GetGames(rr, req)
+
+			if got := parameterError(t, rr); rr.Code != http.StatusBadRequest || got.Message != want {
+				t.Errorf("got %d %q for %q, want %d %q", rr.Code, got.Message, params, http.StatusBadRequest, want)
+			}
+		}
+	})
+}
+
+func TestGetGame(t *testing.T) {
+	game := Game{Id: 1, Date: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	db := newMockDB()
+	db.getGame = func(id int) (Game, error) {
+		if id != game.Id {
+			return Game{}, sql.ErrNoRows
+		}
+		return game, nil
+	}
+
+	t.Run("returns the game with its highlights", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/games/1", nil)
+		req.SetPathValue("id", "1")
+		rr := httptest.NewRecorder()
+		NewBaseHandler(db, nil).GetGame(rr, req)
+
+		var got GameWithHighlights
+		json.NewDecoder(rr.Body).Decode(&got)
+		if rr.Code != http.StatusOK || got.Id != game.Id || got.Highlights == nil {
+			t.Errorf("got %d %v, want %d %v with highlights", rr.Code, got, http.StatusOK, game)
+		}
+	})
+
+	t.Run("ranks the highlights cached for the game", func(t *testing.T) {
+		sqliteDB, err := NewSqliteHoopWatcherDB(filepath.Join(t.TempDir(), "hoop-watcher-test.db"))
+		if err != nil {
+			t.Fatalf("could not create test db: %v", err)
+		}
+		defer sqliteDB.Close()
+		if err := sqliteDB.InitData("./" + TeamFileName); err != nil {
+			t.Fatalf("could not init test db: %v", err)
+		}
+		game, err := sqliteDB.AddGame(Game{HomeTeam: NBATeam{Id: 16}, AwayTeam: NBATeam{Id: 20}, Date: game.Date})
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		game, err = sqliteDB.GetGame(game.Id)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		reaction := Highlight{Title: "Knicks vs Heat REACTION", URL: url.URL{Scheme: "https", Host: "www.youtube.com", Path: "/watch", RawQuery: "v=react"}}
+		recap := Highlight{Title: "Knicks vs Heat Full Game Highlights", URL: url.URL{Scheme: "https", Host: "www.youtube.com", Path: "/watch", RawQuery: "v=abc"}, Channel: "NBA"}
+		provider := &mockHighlightProvider{
+			searchHighlights: func(query HighlightQuery) ([]Highlight, error) {
+				return []Highlight{reaction, recap}, nil
+			},
+		}
+		cached := NewCachedHighlightProvider(provider, sqliteDB, time.Hour)
+		for _, kind := range []HighlightKind{AnyHighlights, RecapHighlights} {
+			if _, err := FindHighlights(game.HighlightQuery(kind), cached); err != nil {
+				t.Fatalf("Found err: %v", err)
+			}
+		}
+
+		id := fmt.Sprint(game.Id)
+		req, _ := http.NewRequest("GET", "/games/"+id, nil)
+		req.SetPathValue("id", id)
+		rr := httptest.NewRecorder()
+		NewBaseHandler(sqliteDB, nil).GetGame(rr, req)
+
+		var got GameWithHighlights
+		json.NewDecoder(rr.Body).Decode(&got)
+		if rr.Code != http.StatusOK || len(got.Highlights) != 1 {
+			t.Fatalf("got %d %v, want %d with the recap once", rr.Code, got.Highlights, http.StatusOK)
+		}
+		if got.Highlights[0].URL != recap.URL || got.Highlights[0].Score <= 0 {
+			t.Errorf("got %v, want the recap ranked above zero", got.Highlights[0])
+		}
+	})
+
+	for id, want := range map[string]int{"2": http.StatusNotFound, "x": http.StatusBadRequest} {
+		req, _ := http.NewRequest("GET", "/games/"+id, nil)
+		req.SetPathValue("id", id)
+		rr := httptest.NewRecorder()
+		NewBaseHandler(db, nil).GetGame(rr, req)
+
+		if rr.Code != want {
+			t.Errorf("got status %d for game %s, want %d", rr.Code, id, want)
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package hoop_watcher_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,7 +17,7 @@ func TestHighlightQueryString(t *testing.T) {
 	t.Run("it generates Youtube Query string for team and date", func(t *testing.T) {
 		loc, _ := time.LoadLocation("Local")
 		date := time.Date(2023, time.January, 1, 0, 0, 0, 0, loc)
-		got := hoop_watcher.TeamHighlightQueryStringWithDate([]string{"Knicks"}, date)
+		got := hoop_watcher.TeamHighlightQueryStringWithDate([]string{"Knicks"}, date, hoop_watcher.AnyHighlights)
 
 		want := "'Knicks NBA Full Game Highlights January 1, 2023'"
 		if got != want {
@@ -19,3 +25,134 @@ func TestHighlightQueryString(t *testing.T) {
 		}
 	})
 }
+
+type fakeHighlightProvider struct {
+	highlights []hoop_watcher.Highlight
+	gotQuery   hoop_watcher.HighlightQuery
+}
+
+func (f *fakeHighlightProvider) SearchHighlights(query hoop_watcher.HighlightQuery) ([]hoop_watcher.Highlight, error) {
+	f.gotQuery = query
+	return f.highlights, nil
+}
+
+func mustParseURL(t *testing.T, rawURL string) url.URL {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("could not parse url %s: %v", rawURL, err)
+	}
+	return *u
+}
+
+func TestGetHighlights(t *testing.T) {
+	knicks := hoop_watcher.NBATeam{Name: "Knicks", FullName: "New York Knicks", Abbreviation: "NYK"}
+
+	t.Run("it lists highlights from the provider", func(t *testing.T) {
+		provider := &fakeHighlightProvider{
+			highlights: []hoop_watcher.Highlight{
+				{Title: "Knicks vs Heat Full Game Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=abc"), Channel: "NBA"},
+			},
+		}
+		out := &bytes.Buffer{}
+		got, err := hoop_watcher.GetHighlights(hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}}, out, provider)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		if len(got) != 1 || got[0].String() != "https://www.youtube.com/watch?v=abc" {
+			t.Errorf("got %v, want the provider's highlight url", got)
+		}
+		if !strings.Contains(out.String(), "[1] NBA | Knicks vs Heat Full Game Highlights") {
+			t.Errorf("highlight missing from output %q", out.String())
+		}
+		if !reflect.DeepEqual(provider.gotQuery.Teams, []hoop_watcher.NBATeam{knicks}) {
+			t.Errorf("got teams %v, want %v", provider.gotQuery.Teams, []hoop_watcher.NBATeam{knicks})
+		}
+	})
+
+	t.Run("it filters out videos that are not highlights of the team for the TUI", func(t *testing.T) {
+		provider := &fakeHighlightProvider{
+			highlights: []hoop_watcher.Highlight{
+				{Title: "Knicks vs Heat Full Game Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=abc")},
+				{Title: "Lakers vs Suns Full Game Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=def")},
+				{Title: "Knicks postgame press conference", URL: mustParseURL(t, "https://www.youtube.com/watch?v=ghi")},
+			},
+		}
+		got, err := hoop_watcher.GetHighlightsForTUI(hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}}, provider)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		if len(got) != 1 || got[0].Title != "Knicks vs Heat Full Game Highlights" {
+			t.Errorf("got %v, want only the Knicks highlight", got)
+		}
+	})
+}
+
+func TestGetHighlightsForDate(t *testing.T) {
+	knicks := hoop_watcher.NBATeam{Name: "Knicks", FullName: "New York Knicks", Abbreviation: "NYK"}
+	gameDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("it rejects videos published before the game", func(t *testing.T) {
+		provider := &fakeHighlightProvider{
+			highlights: []hoop_watcher.Highlight{
+				{Title: "Knicks Full Game Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=old"), PublishedAt: gameDate.Add(-24 * time.Hour)},
+				{Title: "Knicks Full Game Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=new"), PublishedAt: gameDate.Add(28 * time.Hour)},
+			},
+		}
+		got, err := hoop_watcher.GetHighlights(hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}, Date: gameDate}, io.Discard, provider)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		if len(got) != 1 || got[0].String() != "https://www.youtube.com/watch?v=new" {
+			t.Errorf("got %v, want only the video published after the game", got)
+		}
+	})
+}
+
+func TestHighlightJSON(t *testing.T) {
+	t.Run("it round trips a highlight through JSON", func(t *testing.T) {
+		want := hoop_watcher.Highlight{
+			Title:        "Knicks vs Heat Full Game Highlights",
+			URL:          mustParseURL(t, "https://www.youtube.com/watch?v=abc"),
+			Channel:      "NBA",
+			ChannelId:    "UCWJ2lWNubArHWmf3FIHbfcQ",
+			PublishedAt:  time.Date(2023, time.January, 2, 4, 0, 0, 0, time.UTC),
+			Duration:     10*time.Minute + 32*time.Second,
+			ViewCount:    1234567,
+			ThumbnailURL: "https://i.ytimg.com/vi/abc/hqdefault.jpg",
+			Definition:   "hd",
+		}
+		data, err := json.Marshal(want)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if !strings.Contains(string(data), `"url":"https://www.youtube.com/watch?v=abc"`) || !strings.Contains(string(data), `"duration_seconds":632`) {
+			t.Errorf("unexpected JSON %s", data)
+		}
+
+		var got hoop_watcher.Highlight
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		0:                               "-",
+		45 * time.Second:                "0:45",
+		10*time.Minute + 32*time.Second: "10:32",
+		time.Hour + 2*time.Minute + 3*time.Second: "1:02:03",
+	}
+	for duration, want := range cases {
+		if got := hoop_watcher.FormatDuration(duration); got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	}
+}
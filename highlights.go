@@ -1,99 +1,239 @@
 package hoop_watcher
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/url"
 	"strings"
 	"time"
-
-	"google.golang.org/api/youtube/v3"
 )
 
 const DAILY_DATE_FORMAT = "2006-01-02"
 const HUMAN_DATE_FORMAT = "January 2, 2006"
 
-func TeamHighlightQueryStringWithDate(teamNames []string, reqTime time.Time) string {
+func TeamHighlightQueryStringWithDate(teamNames []string, reqTime time.Time, kind HighlightKind) string {
 	dateStr := reqTime.Format(HUMAN_DATE_FORMAT)
-	return fmt.Sprintf("'%s NBA Full Game Highlights %s'", strings.Join(teamNames, " vs "), dateStr)
+	return fmt.Sprintf("'%s NBA %s %s'", strings.Join(teamNames, " vs "), kind.queryTerm(), dateStr)
 }
 
-func TeamHighlightQueryString(teamNames []string) string {
-	return fmt.Sprintf("'%s NBA Full Game Highlights'", strings.Join(teamNames, " vs "))
+func TeamHighlightQueryString(teamNames []string, kind HighlightKind) string {
+	return fmt.Sprintf("'%s NBA %s'", strings.Join(teamNames, " vs "), kind.queryTerm())
 }
 
-// searchListByQ searches for videos based on a keyword query
-func searchListByQ(service *youtube.Service, keywordQuery string, maxResults int64) ([]*youtube.SearchResult, error) {
-	call := service.Search.List([]string{"id", "snippet"}).
-		Q(keywordQuery).
-		Type("video").MaxResults(maxResults)
+// HighlightPublishWindow is how long after the game date a highlight can be
+// published and still be considered for that game.
+const HighlightPublishWindow = 72 * time.Hour
+
+type Highlight struct {
+	Title        string
+	URL          url.URL
+	Channel      string
+	ChannelId    string
+	Trusted      bool
+	PublishedAt  time.Time
+	Score        int
+	Duration     time.Duration
+	ViewCount    uint64
+	ThumbnailURL string
+	Definition   string
+}
 
-	response, err := call.Do()
+// highlightJSON is the wire format of a Highlight, shared by the server and
+// anything reading its responses.
+type highlightJSON struct {
+	Title           string    `json:"title"`
+	URL             string    `json:"url"`
+	Channel         string    `json:"channel"`
+	ChannelId       string    `json:"channel_id"`
+	Trusted         bool      `json:"trusted"`
+	PublishedAt     time.Time `json:"published_at"`
+	Score           int       `json:"score"`
+	DurationSeconds int64     `json:"duration_seconds"`
+	ViewCount       uint64    `json:"view_count"`
+	ThumbnailURL    string    `json:"thumbnail_url"`
+	Definition      string    `json:"definition"`
+}
+
+func (h Highlight) MarshalJSON() ([]byte, error) {
+	return json.Marshal(highlightJSON{
+		Title:           h.Title,
+		URL:             h.URL.String(),
+		Channel:         h.Channel,
+		ChannelId:       h.ChannelId,
+		Trusted:         h.Trusted,
+		PublishedAt:     h.PublishedAt,
+		Score:           h.Score,
+		DurationSeconds: int64(h.Duration / time.Second),
+		ViewCount:       h.ViewCount,
+		ThumbnailURL:    h.ThumbnailURL,
+		Definition:      h.Definition,
+	})
+}
+
+func (h *Highlight) UnmarshalJSON(data []byte) error {
+	var raw highlightJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	parsedURL, err := url.Parse(raw.URL)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	*h = Highlight{
+		Title:        raw.Title,
+		URL:          *parsedURL,
+		Channel:      raw.Channel,
+		ChannelId:    raw.ChannelId,
+		Trusted:      raw.Trusted,
+		PublishedAt:  raw.PublishedAt,
+		Score:        raw.Score,
+		Duration:     time.Duration(raw.DurationSeconds) * time.Second,
+		ViewCount:    raw.ViewCount,
+		ThumbnailURL: raw.ThumbnailURL,
+		Definition:   raw.Definition,
+	}
+	return nil
+}
 
-	return response.Items, nil
+// FormatDuration renders a video length the way video sites do, e.g. 10:32
+// or 1:02:03.
+func FormatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	totalSeconds := int(d / time.Second)
+	hours, minutes, seconds := totalSeconds/3600, totalSeconds/60%60, totalSeconds%60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
 }
 
-type Highlight struct {
-	Title string
-	URL   url.URL
+// FormatViewCount shortens a view count, e.g. 1.2M or 45K.
+func FormatViewCount(views uint64) string {
+	switch {
+	case views >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(views)/1_000_000)
+	case views >= 1_000:
+		return fmt.Sprintf("%dK", views/1_000)
+	default:
+		return fmt.Sprintf("%d", views)
+	}
 }
 
-func isHighlightVideoForTeam(searchResult *youtube.SearchResult, team NBATeam) bool {
-	teamMatchTokens := getTeamMatchTokens(team)
-	shortenedTeamName := teamMatchTokens[3]
-	videoTitle := strings.ToLower(searchResult.Snippet.Title)
+// FormatPublishedAt renders a publish time as a date, or "-" when unknown.
+func FormatPublishedAt(publishedAt time.Time) string {
+	if publishedAt.IsZero() {
+		return "-"
+	}
+	return publishedAt.Local().Format(DAILY_DATE_FORMAT)
+}
 
-	return strings.Contains(videoTitle, shortenedTeamName) && strings.Contains(videoTitle, "highlights")
+// HighlightQuery describes the game to find highlights for. A zero Date means
+// the most recent game.
+type HighlightQuery struct {
+	Teams []NBATeam
+	Date  time.Time
+	Kind  HighlightKind
 }
 
-func GetHighlightsForTUI(team NBATeam, time time.Time, youtubeClient *youtube.Service) (highlights []Highlight) {
+func (q HighlightQuery) TeamNames() []string {
 	teamNames := []string{}
-	teamNames = append(teamNames, team.Name)
-	youtubeQueryString := TeamHighlightQueryString(teamNames)
-	videos, err := searchListByQ(youtubeClient, youtubeQueryString, 5)
-	if err != nil {
-		log.Fatalf("Error occurred fething youtube video urls")
+	for _, t := range q.Teams {
+		teamNames = append(teamNames, t.Name)
+	}
+	return teamNames
+}
+
+// HighlightProvider is a source of highlight videos for NBA games.
+type HighlightProvider interface {
+	SearchHighlights(query HighlightQuery) ([]Highlight, error)
+}
+
+// HighlightQueryString builds the search query for the teams' game on date,
+// leaving the date out when it is zero.
+func HighlightQueryString(teamNames []string, date time.Time, kind HighlightKind) string {
+	if date.IsZero() {
+		return TeamHighlightQueryString(teamNames, kind)
 	}
+	return TeamHighlightQueryStringWithDate(teamNames, date, kind)
+}
+
+// HighlightSearchWindow returns the range of publish times a highlight for a
+// game on date can fall in.
+func HighlightSearchWindow(date time.Time) (publishedAfter time.Time, publishedBefore time.Time) {
+	year, month, day := date.Date()
+	publishedAfter = time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	return publishedAfter, publishedAfter.Add(HighlightPublishWindow)
+}
 
-	for _, video := range videos {
-		videoURL := fmt.Sprintf("https://www.youtube.com/watch?v=%v", video.Id.VideoId)
-		parsedUrl, err := url.Parse(videoURL)
-		if err != nil {
-			log.Fatalf("Error parsing video URL")
+// publishedForGame drops highlights published before the game on date.
+// Highlights without a publish time are kept.
+func publishedForGame(highlights []Highlight, date time.Time) []Highlight {
+	if date.IsZero() {
+		return highlights
+	}
+	publishedAfter, _ := HighlightSearchWindow(date)
+	filtered := []Highlight{}
+	for _, highlight := range highlights {
+		if !highlight.PublishedAt.IsZero() && highlight.PublishedAt.Before(publishedAfter) {
+			continue
 		}
-		if isHighlightVideoForTeam(video, team) {
-			highlights = append(highlights, Highlight{Title: video.Snippet.Title, URL: *parsedUrl})
+		filtered = append(filtered, highlight)
+	}
+	return filtered
+}
+
+func isRelevantHighlight(highlight Highlight) bool {
+	return highlight.Score > 0
+}
+
+// filterHighlights drops the results that cannot be the query's highlights and
+// ranks the rest.
+func filterHighlights(highlights []Highlight, query HighlightQuery) []Highlight {
+	highlights = filterByKind(publishedForGame(highlights, query.Date), query.Kind)
+	return RankHighlights(highlights, query.Teams, query.Date)
+}
+
+func GetHighlightsForTUI(query HighlightQuery, provider HighlightProvider) (highlights []Highlight, err error) {
+	results, err := FindHighlights(query, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, highlight := range results {
+		if isRelevantHighlight(highlight) {
+			highlights = append(highlights, highlight)
 		}
 	}
-	return highlights
+	return highlights, nil
 }
 
-func GetHighlights(teams []NBATeam, out io.Writer, youtubeClient *youtube.Service, time time.Time) []url.URL {
-	teamNames := []string{}
-	for _, t := range teams {
-		teamNames = append(teamNames, t.Name)
+// FindHighlights searches provider for the query's highlights, best match
+// first.
+func FindHighlights(query HighlightQuery, provider HighlightProvider) ([]Highlight, error) {
+	highlights, err := provider.SearchHighlights(query)
+	if err != nil {
+		return nil, err
 	}
-	fmt.Fprintf(out, "Getting highlights for the %v\n\n", strings.Join(teamNames, " vs "))
-	youtubeQueryString := TeamHighlightQueryString(teamNames)
-	videos, err := searchListByQ(youtubeClient, youtubeQueryString, 5)
+	return filterHighlights(highlights, query), nil
+}
+
+func GetHighlights(query HighlightQuery, out io.Writer, provider HighlightProvider) ([]url.URL, error) {
+	fmt.Fprintf(out, "Getting highlights for the %v\n\n", strings.Join(query.TeamNames(), " vs "))
+	highlights, err := FindHighlights(query, provider)
 	if err != nil {
-		log.Fatalf("Error occurred fething youtube video urls")
+		return nil, err
 	}
 
 	fmt.Fprintln(out, "Found these matching highlights:")
+	if err := WriteHighlights(out, highlights, OutputText); err != nil {
+		return nil, err
+	}
 	var highlightUrls []url.URL
-	for i, video := range videos {
-		fmt.Fprintf(out, "[%d] %s | %s\n", i+1, video.Snippet.ChannelTitle, video.Snippet.Title)
-		videoURL := fmt.Sprintf("https://www.youtube.com/watch?v=%v", video.Id.VideoId)
-		parsedUrl, err := url.Parse(videoURL)
-		if err != nil {
-			log.Fatalf("Error parsing video URL")
-		}
-		highlightUrls = append(highlightUrls, *parsedUrl)
+	for _, highlight := range highlights {
+		highlightUrls = append(highlightUrls, highlight.URL)
 	}
-	return highlightUrls
+	return highlightUrls, nil
 }
@@ -0,0 +1,96 @@
+package hoop_watcher
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HighlightKind is the cut of a game a highlight video shows.
+type HighlightKind string
+
+const (
+	AnyHighlights       HighlightKind = ""
+	RecapHighlights     HighlightKind = "recap"
+	CondensedHighlights HighlightKind = "condensed"
+	TopPlaysHighlights  HighlightKind = "plays"
+)
+
+// HighlightKinds lists the kinds in the order the TUI cycles through them.
+var HighlightKinds = []HighlightKind{
+	AnyHighlights,
+	RecapHighlights,
+	CondensedHighlights,
+	TopPlaysHighlights,
+}
+
+const (
+	maxRecapDuration     = 12 * time.Minute
+	minCondensedDuration = 15 * time.Minute
+	maxCondensedDuration = 40 * time.Minute
+)
+
+func ParseHighlightKind(kindStr string) (HighlightKind, error) {
+	kind := HighlightKind(strings.ToLower(strings.TrimSpace(kindStr)))
+	for _, k := range HighlightKinds {
+		if kind == k {
+			return kind, nil
+		}
+	}
+	return AnyHighlights, fmt.Errorf("Invalid highlight kind %q, expected one of recap, condensed or plays", kindStr)
+}
+
+func (k HighlightKind) String() string {
+	if k == AnyHighlights {
+		return "any"
+	}
+	return string(k)
+}
+
+// Next returns the kind after k in HighlightKinds, wrapping around.
+func (k HighlightKind) Next() HighlightKind {
+	for i, kind := range HighlightKinds {
+		if kind == k {
+			return HighlightKinds[(i+1)%len(HighlightKinds)]
+		}
+	}
+	return AnyHighlights
+}
+
+// queryTerm is what the kind adds to a search query.
+func (k HighlightKind) queryTerm() string {
+	switch k {
+	case CondensedHighlights:
+		return "Condensed Game"
+	case TopPlaysHighlights:
+		return "Top Plays"
+	default:
+		return "Full Game Highlights"
+	}
+}
+
+// MatchesDuration reports whether a video of length d is the kind's cut. An
+// unknown length always matches.
+func (k HighlightKind) MatchesDuration(d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	switch k {
+	case RecapHighlights:
+		return d < maxRecapDuration
+	case CondensedHighlights:
+		return minCondensedDuration <= d && d <= maxCondensedDuration
+	default:
+		return true
+	}
+}
+
+func filterByKind(highlights []Highlight, kind HighlightKind) []Highlight {
+	filtered := []Highlight{}
+	for _, highlight := range highlights {
+		if kind.MatchesDuration(highlight.Duration) {
+			filtered = append(filtered, highlight)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,73 @@
+package hoop_watcher_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+func TestHighlightKinds(t *testing.T) {
+	t.Run("it parses highlight kinds", func(t *testing.T) {
+		cases := map[string]hoop_watcher.HighlightKind{
+			"":          hoop_watcher.AnyHighlights,
+			"recap":     hoop_watcher.RecapHighlights,
+			"Condensed": hoop_watcher.CondensedHighlights,
+			" plays ":   hoop_watcher.TopPlaysHighlights,
+		}
+		for kindStr, want := range cases {
+			got, err := hoop_watcher.ParseHighlightKind(kindStr)
+			if err != nil {
+				t.Fatalf("Found err: %v", err)
+			}
+			if got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		}
+
+		if _, err := hoop_watcher.ParseHighlightKind("bloopers"); err == nil {
+			t.Error("Expected error but err was nil")
+		}
+	})
+
+	t.Run("it builds the query for the kind", func(t *testing.T) {
+		got := hoop_watcher.TeamHighlightQueryString([]string{"Knicks", "Heat"}, hoop_watcher.CondensedHighlights)
+		want := "'Knicks vs Heat NBA Condensed Game'"
+		if got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("it filters highlights by duration", func(t *testing.T) {
+		knicks := hoop_watcher.NBATeam{Name: "Knicks", FullName: "New York Knicks", Abbreviation: "NYK"}
+		provider := &fakeHighlightProvider{
+			highlights: []hoop_watcher.Highlight{
+				{Title: "Knicks Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=clip"), Duration: 45 * time.Second},
+				{Title: "Knicks Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=recap"), Duration: 10 * time.Minute},
+				{Title: "Knicks Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=condensed"), Duration: 25 * time.Minute},
+				{Title: "Knicks Highlights", URL: mustParseURL(t, "https://www.youtube.com/watch?v=full"), Duration: 2 * time.Hour},
+			},
+		}
+		cases := map[hoop_watcher.HighlightKind][]string{
+			hoop_watcher.AnyHighlights:       {"clip", "recap", "condensed", "full"},
+			hoop_watcher.RecapHighlights:     {"clip", "recap"},
+			hoop_watcher.CondensedHighlights: {"condensed"},
+		}
+		for kind, want := range cases {
+			query := hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}, Kind: kind}
+			got, err := hoop_watcher.GetHighlights(query, io.Discard, provider)
+			if err != nil {
+				t.Fatalf("Found err: %v", err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got %v for %s, want videos %v", got, kind, want)
+			}
+			for i, videoId := range want {
+				if got[i].Query().Get("v") != videoId {
+					t.Errorf("got %v for %s, want videos %v", got, kind, want)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package hoop_watcher
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const OpenAPIFileName = "openapi.json"
+
+//go:embed openapi.json
+var openAPIJSON []byte
+
+// OpenAPISpec is the part of an OpenAPI 3 document the server uses to
+// validate requests and render its docs.
+type OpenAPISpec struct {
+	Info OpenAPIInfo `json:"info"`
+	// Paths maps a path, then a lower case method, to its operation.
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components struct {
+		Parameters map[string]*OpenAPIParameter `json:"parameters"`
+		Responses  map[string]*OpenAPIResponse  `json:"responses"`
+		Schemas    map[string]*OpenAPISchema    `json:"schemas"`
+	} `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type OpenAPIOperation struct {
+	OperationId string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Parameters  []*OpenAPIParameter         `json:"parameters"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIParameter struct {
+	Ref         string         `json:"$ref"`
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Required    bool           `json:"required"`
+	Description string         `json:"description"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIResponse struct {
+	Ref         string `json:"$ref"`
+	Description string `json:"description"`
+	Content     map[string]struct {
+		Schema *OpenAPISchema `json:"schema"`
+	} `json:"content"`
+}
+
+type OpenAPISchema struct {
+	Ref        string                    `json:"$ref"`
+	Type       string                    `json:"type"`
+	Format     string                    `json:"format"`
+	Enum       []string                  `json:"enum"`
+	Minimum    *int                      `json:"minimum"`
+	Maximum    *int                      `json:"maximum"`
+	MinLength  int                       `json:"minLength"`
+	Items      *OpenAPISchema            `json:"items"`
+	Properties map[string]*OpenAPISchema `json:"properties"`
+	Required   []string                  `json:"required"`
+}
+
+// DefaultOpenAPISpec is the spec in openapi.json.
+var DefaultOpenAPISpec = mustParseOpenAPISpec(openAPIJSON)
+
+func mustParseOpenAPISpec(data []byte) *OpenAPISpec {
+	spec, err := ParseOpenAPISpec(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid %s: %v", OpenAPIFileName, err))
+	}
+	return spec
+}
+
+// ParseOpenAPISpec parses an OpenAPI document, replacing the $refs of
+// parameters and responses with the components they point to.
+func ParseOpenAPISpec(data []byte) (*OpenAPISpec, error) {
+	var spec OpenAPISpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	for path, operations := range spec.Paths {
+		for method, operation := range operations {
+			for i, parameter := range operation.Parameters {
+				if parameter.Ref == "" {
+					continue
+				}
+				component, ok := spec.Components.Parameters[strings.TrimPrefix(parameter.Ref, "#/components/parameters/")]
+				if !ok {
+					return nil, fmt.Errorf("unknown parameter %s in %s %s", parameter.Ref, method, path)
+				}
+				operation.Parameters[i] = component
+			}
+			for status, response := range operation.Responses {
+				if response.Ref == "" {
+					continue
+				}
+				component, ok := spec.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]
+				if !ok {
+					return nil, fmt.Errorf("unknown response %s in %s %s", response.Ref, method, path)
+				}
+				operation.Responses[status] = component
+			}
+		}
+	}
+	return &spec, nil
+}
+
+// Schema returns the schema ref points to, or nil if there is none.
+func (s *OpenAPISpec) Schema(ref string) *OpenAPISchema {
+	return s.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+}
+
+// Operation returns the operation of a ServeMux pattern such as
+// "GET /teams/{abbrev}", or nil if the spec does not describe it.
+func (s *OpenAPISpec) Operation(pattern string) *OpenAPIOperation {
+	method, path, _ := strings.Cut(pattern, " ")
+	return s.Paths[strings.TrimSuffix(path, "{$}")][strings.ToLower(method)]
+}
+
+// ParameterError is the body of the 400 response to a request with a missing
+// or invalid parameter.
+type ParameterError struct {
+	Message   string `json:"error"`
+	Parameter string `json:"parameter"`
+	// In is where the parameter goes, query or path.
+	In string `json:"in"`
+}
+
+func (e *ParameterError) Error() string {
+	return e.Message
+}
+
+func missingParameter(name string, in string) *ParameterError {
+	return &ParameterError{Message: fmt.Sprintf("Missing %s %s parameter", name, in), Parameter: name, In: in}
+}
+
+func invalidParameter(name string, in string) *ParameterError {
+	return &ParameterError{Message: fmt.Sprintf("Invalid %s %s parameter", name, in), Parameter: name, In: in}
+}
+
+// writeBadRequest responds with err as a ParameterError.
+func writeBadRequest(w http.ResponseWriter, err error) {
+	body, ok := err.(*ParameterError)
+	if !ok {
+		body = &ParameterError{Message: err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(body)
+}
+
+// validValue reports whether a parameter's value matches its schema.
+func validValue(schema *OpenAPISchema, value string) bool {
+	if schema == nil {
+		return true
+	}
+	if len(value) < schema.MinLength || (len(schema.Enum) > 0 && !slices.Contains(schema.Enum, value)) {
+		return false
+	}
+	switch schema.Type {
+	case "integer":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return false
+		}
+		return (schema.Minimum == nil || n >= *schema.Minimum) && (schema.Maximum == nil || n <= *schema.Maximum)
+	case "string":
+		if schema.Format == "date" {
+			_, err := time.Parse(DAILY_DATE_FORMAT, value)
+			return err == nil
+		}
+	}
+	return true
+}
+
+// ValidateRequest checks the request's parameters against operation.
+func ValidateRequest(operation *OpenAPIOperation, r *http.Request) error {
+	query := r.URL.Query()
+	for _, parameter := range operation.Parameters {
+		var value string
+		switch parameter.In {
+		case "query":
+			value = query.Get(parameter.Name)
+		case "path":
+			value = r.PathValue(parameter.Name)
+		default:
+			continue
+		}
+		if strings.TrimSpace(value) == "" {
+			if parameter.Required {
+				return missingParameter(parameter.Name, parameter.In)
+			}
+			continue
+		}
+		if !validValue(parameter.Schema, value) {
+			return invalidParameter(parameter.Name, parameter.In)
+		}
+	}
+	return nil
+}
+
+// validateRequests responds 400 to requests whose parameters do not match
+// operation, before they reach next.
+func validateRequests(operation *OpenAPIOperation, next http.HandlerFunc) http.HandlerFunc {
+	if operation == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := ValidateRequest(operation, r); err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (h *BaseHandler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPIJSON)
+}
+
+var docsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Info.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; }
+code { background: #f4f4f4; padding: 0 .2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+td, th { border: 1px solid #ddd; padding: .2em .5em; text-align: left; }
+</style>
+</head>
+<body>
+<h1>{{.Info.Title}} <small>{{.Info.Version}}</small></h1>
+<p>{{.Info.Description}} The OpenAPI document is at <a href="/openapi.json">/openapi.json</a>.</p>
+{{range .Routes}}
+<h2 id="{{.Operation.OperationId}}"><code>{{.Method}} {{.Path}}</code></h2>
+<p>{{.Operation.Summary}}</p>
+{{if .Operation.Parameters}}<table>
+<tr><th>Parameter</th><th>In</th><th>Type</th><th>Description</th></tr>
+{{range .Operation.Parameters}}<tr><td><code>{{.Name}}</code>{{if .Required}} (required){{end}}</td><td>{{.In}}</td><td>{{with .Schema}}{{.Type}}{{with .Format}} ({{.}}){{end}}{{with .Enum}} one of {{range $i, $v := .}}{{if $i}}, {{end}}{{$v}}{{end}}{{end}}{{end}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>{{end}}
+<p>Responses: {{range $i, $status := .Statuses}}{{if $i}}, {{end}}{{$status}}{{end}}</p>
+{{end}}
+</body>
+</html>
+`))
+
+type docsRoute struct {
+	Method    string
+	Path      string
+	Operation *OpenAPIOperation
+	Statuses  []string
+}
+
+// GetDocs renders a page describing every operation in the spec.
+func (h *BaseHandler) GetDocs(w http.ResponseWriter, r *http.Request) {
+	routes := []docsRoute{}
+	for path, operations := range DefaultOpenAPISpec.Paths {
+		for method, operation := range operations {
+			statuses := []string{}
+			for status := range operation.Responses {
+				statuses = append(statuses, status)
+			}
+			sort.Strings(statuses)
+			routes = append(routes, docsRoute{Method: strings.ToUpper(method), Path: path, Operation: operation, Statuses: statuses})
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	page := &bytes.Buffer{}
+	if err := docsTemplate.Execute(page, struct {
+		Info   OpenAPIInfo
+		Routes []docsRoute
+	}{DefaultOpenAPISpec.Info, routes}); err != nil {
+		handleDBError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	page.WriteTo(w)
+}
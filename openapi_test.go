@@ -0,0 +1,289 @@
+package hoop_watcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// specOperations lists the spec's operations as ServeMux patterns.
+func specOperations(spec *OpenAPISpec) []string {
+	patterns := []string{}
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if path == "/" {
+				path = "/{$}"
+			}
+			patterns = append(patterns, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	routes := []string{}
+	for pattern := range NewBaseHandler(newMockDB(), nil).Routes() {
+		routes = append(routes, pattern)
+	}
+	sort.Strings(routes)
+
+	for _, pattern := range routes {
+		if DefaultOpenAPISpec.Operation(pattern) == nil {
+			t.Errorf("route %s is missing from %s", pattern, OpenAPIFileName)
+		}
+	}
+	for _, pattern := range specOperations(DefaultOpenAPISpec) {
+		if !slices.Contains(routes, pattern) {
+			t.Errorf("%s describes %s, which is not a route", OpenAPIFileName, pattern)
+		}
+	}
+}
+
+// checkSchema fails t if value, decoded from JSON, does not match schema.
+// Objects must have every required property and no undocumented ones.
+func checkSchema(t *testing.T, spec *OpenAPISpec, schema *OpenAPISchema, value interface{}, where string) {
+	t.Helper()
+	if schema.Ref != "" {
+		schema = spec.Schema(schema.Ref)
+		if schema == nil {
+			t.Fatalf("%s: unknown schema", where)
+		}
+	}
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			t.Errorf("%s: got %v, want an object", where, value)
+			return
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				t.Errorf("%s: missing required property %s", where, name)
+			}
+		}
+		if schema.Properties == nil {
+			return
+		}
+		for name, property := range object {
+			propertySchema, ok := schema.Properties[name]
+			if !ok {
+				t.Errorf("%s: property %s is not in the spec", where, name)
+				continue
+			}
+			checkSchema(t, spec, propertySchema, property, where+"."+name)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			t.Errorf("%s: got %v, want an array", where, value)
+			return
+		}
+		for i, item := range items {
+			checkSchema(t, spec, schema.Items, item, fmt.Sprintf("%s[%d]", where, i))
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok || (schema.Type == "integer" && n != float64(int64(n))) {
+			t.Errorf("%s: got %v, want an %s", where, value, schema.Type)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			t.Errorf("%s: got %v, want a boolean", where, value)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			t.Errorf("%s: got %v, want a string", where, value)
+			return
+		}
+		if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, s) {
+			t.Errorf("%s: got %q, want one of %v", where, s, schema.Enum)
+		}
+		if _, err := time.Parse(time.RFC3339, s); schema.Format == "date-time" && err != nil {
+			t.Errorf("%s: got %q, want a date-time", where, s)
+		}
+	}
+}
+
+// newSpecTestServer serves the API over a SQLite database holding one game
+// with a cached highlight.
+func newSpecTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	db, err := NewSqliteHoopWatcherDB(filepath.Join(t.TempDir(), "hoop-watcher-test.db"))
+	if err != nil {
+		t.Fatalf("could not create test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.InitData("./" + TeamFileName); err != nil {
+		t.Fatalf("could not init test db: %v", err)
+	}
+	knicks, _ := db.GetTeamByAbbrev("NYK")
+	heat, _ := db.GetTeamByAbbrev("MIA")
+	date := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	game, err := db.AddGame(Game{HomeTeam: heat, AwayTeam: knicks, Date: date})
+	if err != nil {
+		t.Fatalf("could not add game: %v", err)
+	}
+	highlight := Highlight{
+		Title:       "Knicks vs Heat Full Game Highlights",
+		URL:         url.URL{Scheme: "https", Host: "www.youtube.com", Path: "/watch", RawQuery: "v=abc"},
+		Channel:     "NBA",
+		Trusted:     true,
+		PublishedAt: date.Add(26 * time.Hour),
+		Duration:    10 * time.Minute,
+	}
+	if err := db.CacheHighlights(game.HighlightQuery(AnyHighlights), []Highlight{highlight}, time.Now()); err != nil {
+		t.Fatalf("could not cache highlights: %v", err)
+	}
+	if err := db.SetTeamFavorite(knicks.Id, true); err != nil {
+		t.Fatalf("could not favorite team: %v", err)
+	}
+
+	provider := &mockHighlightProvider{
+		searchHighlights: func(query HighlightQuery) ([]Highlight, error) {
+			return []Highlight{highlight}, nil
+		},
+	}
+	router := http.NewServeMux()
+	NewBaseHandler(db, provider).RegisterRoutes(router)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAPIResponses(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	server := newSpecTestServer(t)
+
+	requests := []struct {
+		pattern string
+		method  string
+		path    string
+	}{
+		{"GET /{$}", "GET", "/"},
+		{"GET /openapi.json", "GET", "/openapi.json"},
+		{"GET /docs", "GET", "/docs"},
+		{"GET /teams", "GET", "/teams"},
+		{"GET /teams/search", "GET", "/teams/search?q=new"},
+		{"GET /teams/search", "GET", "/teams/search"},
+		{"GET /teams/favorites", "GET", "/teams/favorites"},
+		{"GET /teams/{abbrev}", "GET", "/teams/NYK"},
+		{"GET /teams/{abbrev}", "GET", "/teams/SEA"},
+		{"GET /teams/{abbrev}/highlights", "GET", "/teams/NYK/highlights?date=2023-01-01&limit=1"},
+		{"GET /teams/{abbrev}/highlights", "GET", "/teams/NYK/highlights?date=2023-01-01&opponent=NYK"},
+		{"GET /teams/{abbrev}/games", "GET", "/teams/NYK/games?from=2023-01-01"},
+		{"GET /teams/{abbrev}/games", "GET", "/teams/SEA/games"},
+		{"PUT /teams/{abbrev}/favorite", "PUT", "/teams/MIA/favorite"},
+		{"DELETE /teams/{abbrev}/favorite", "DELETE", "/teams/MIA/favorite"},
+		{"DELETE /teams/{abbrev}/favorite", "DELETE", "/teams/SEA/favorite"},
+		{"GET /games", "GET", "/games?date=2023-01-01"},
+		{"GET /games", "GET", "/games?from=2023-01-02&to=2023-01-01"},
+		{"GET /games/{id}", "GET", "/games/1"},
+		{"GET /games/{id}", "GET", "/games/2"},
+	}
+
+	succeeded := map[string]bool{}
+	for _, r := range requests {
+		req, _ := http.NewRequest(r.method, server.URL+r.path, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("could not request %s %s: %v", r.method, r.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		where := fmt.Sprintf("%s %s", r.method, r.path)
+
+		response, ok := DefaultOpenAPISpec.Operation(r.pattern).Responses[fmt.Sprint(resp.StatusCode)]
+		if !ok {
+			t.Errorf("%s: status %d is not in the spec", where, resp.StatusCode)
+			continue
+		}
+		if resp.StatusCode < 300 {
+			succeeded[r.pattern] = true
+		}
+		if len(response.Content) == 0 {
+			continue
+		}
+		mediaType := strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+		content, ok := response.Content[mediaType]
+		if !ok {
+			t.Errorf("%s: content type %s is not in the spec", where, mediaType)
+			continue
+		}
+		if mediaType != "application/json" {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			t.Errorf("%s: could not decode %q: %v", where, body, err)
+			continue
+		}
+		checkSchema(t, DefaultOpenAPISpec, content.Schema, value, where)
+	}
+
+	for _, pattern := range specOperations(DefaultOpenAPISpec) {
+		if !succeeded[pattern] {
+			t.Errorf("no successful request checks %s against the spec", pattern)
+		}
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	server := newSpecTestServer(t)
+
+	cases := map[string]ParameterError{
+		"/teams/NYK/highlights?date=2023-1-1":               {Message: "Invalid date query parameter", Parameter: "date", In: "query"},
+		"/teams/NYK/highlights?date=2023-01-01&kind=RECAPS": {Message: "Invalid kind query parameter", Parameter: "kind", In: "query"},
+		"/teams/search?q=":                                  {Message: "Missing q query parameter", Parameter: "q", In: "query"},
+		"/games?limit=500":                                  {Message: "Invalid limit query parameter", Parameter: "limit", In: "query"},
+		"/games?offset=x":                                   {Message: "Invalid offset query parameter", Parameter: "offset", In: "query"},
+		"/teams/NYK/games?to=tomorrow":                      {Message: "Invalid to query parameter", Parameter: "to", In: "query"},
+		"/games/x":                                          {Message: "Invalid id path parameter", Parameter: "id", In: "path"},
+		"/games/0":                                          {Message: "Invalid id path parameter", Parameter: "id", In: "path"},
+	}
+	for path, want := range cases {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("could not request %s: %v", path, err)
+		}
+		var got ParameterError
+		json.NewDecoder(resp.Body).Decode(&got)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest || got != want {
+			t.Errorf("got %d %v for %s, want %d %v", resp.StatusCode, got, path, http.StatusBadRequest, want)
+		}
+		if contentType := resp.Header.Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("got content type %s for %s, want application/json", contentType, path)
+		}
+	}
+}
+
+func TestGetDocs(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/docs", nil)
+	rr := httptest.NewRecorder()
+	NewBaseHandler(newMockDB(), nil).GetDocs(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", rr.Code, http.StatusOK)
+	}
+	for path, operations := range DefaultOpenAPISpec.Paths {
+		for method := range operations {
+			if want := strings.ToUpper(method) + " " + path; !strings.Contains(rr.Body.String(), want) {
+				t.Errorf("docs are missing %s", want)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package hoop_watcher
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const PlayerEnv = "HOOP_WATCHER_PLAYER"
+
+// urlPlaceholder is replaced by the highlight URL in a player command.
+const urlPlaceholder = "{url}"
+
+// Opener opens a highlight in a browser or media player.
+type Opener interface {
+	Command(u url.URL) *exec.Cmd
+	Open(u url.URL) error
+}
+
+// CommandOpener runs a program with the highlight URL in place of {url} in
+// its arguments.
+type CommandOpener struct {
+	Name string
+	Args []string
+	// shellQuoted quotes the URL for a POSIX shell command line.
+	shellQuoted bool
+}
+
+// NewPlatformOpener opens highlights with the default handler of goos.
+func NewPlatformOpener(goos string) CommandOpener {
+	switch goos {
+	case "darwin":
+		return CommandOpener{Name: "open", Args: []string{urlPlaceholder}}
+	case "windows":
+		return CommandOpener{Name: "cmd", Args: []string{"/c", "start", "", urlPlaceholder}}
+	default:
+		return CommandOpener{Name: "xdg-open", Args: []string{urlPlaceholder}}
+	}
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// NewPlayerOpener opens highlights with a shell command such as "mpv {url}"
+// or "yt-dlp -o - {url} | mpv -". The URL is appended when the command has no
+// {url} placeholder. On Windows the player is run directly instead, since cmd
+// would split the URL at its &s, so the command cannot use pipes there.
+func NewPlayerOpener(goos string, playerCommand string) CommandOpener {
+	if !strings.Contains(playerCommand, urlPlaceholder) {
+		playerCommand += " " + urlPlaceholder
+	}
+	if goos == "windows" {
+		fields := strings.Fields(playerCommand)
+		return CommandOpener{Name: fields[0], Args: fields[1:]}
+	}
+	return CommandOpener{Name: "sh", Args: []string{"-c", playerCommand}, shellQuoted: true}
+}
+
+// OpenerFromEnv uses the player command configured in the player environment
+// variable, falling back to the platform's default handler.
+func OpenerFromEnv() CommandOpener {
+	if playerCommand := strings.TrimSpace(os.Getenv(PlayerEnv)); playerCommand != "" {
+		return NewPlayerOpener(runtime.GOOS, playerCommand)
+	}
+	return NewPlatformOpener(runtime.GOOS)
+}
+
+func (o CommandOpener) Command(u url.URL) *exec.Cmd {
+	rawURL := u.String()
+	if o.shellQuoted {
+		rawURL = shellQuote(rawURL)
+	}
+	args := make([]string, len(o.Args))
+	for i, arg := range o.Args {
+		args[i] = strings.ReplaceAll(arg, urlPlaceholder, rawURL)
+	}
+	return exec.Command(o.Name, args...)
+}
+
+// Open runs the command attached to the terminal and waits for it to finish.
+func (o CommandOpener) Open(u url.URL) error {
+	cmd := o.Command(u)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not open highlight with %s: %w", o.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package hoop_watcher_test
+
+import (
+	"reflect"
+	"testing"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+func TestOpener(t *testing.T) {
+	highlightURL := mustParseURL(t, "https://www.youtube.com/watch?v=abc")
+
+	t.Run("it picks the platform's default handler", func(t *testing.T) {
+		cases := map[string][]string{
+			"linux":   {"xdg-open", "https://www.youtube.com/watch?v=abc"},
+			"darwin":  {"open", "https://www.youtube.com/watch?v=abc"},
+			"windows": {"cmd", "/c", "start", "", "https://www.youtube.com/watch?v=abc"},
+		}
+		for goos, want := range cases {
+			got := hoop_watcher.NewPlatformOpener(goos).Command(highlightURL).Args
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v on %s, want %v", got, goos, want)
+			}
+		}
+	})
+
+	t.Run("it runs a player command through the shell", func(t *testing.T) {
+		cases := map[string][]string{
+			"mpv {url}":                 {"sh", "-c", "mpv 'https://www.youtube.com/watch?v=abc'"},
+			"mpv --fs":                  {"sh", "-c", "mpv --fs 'https://www.youtube.com/watch?v=abc'"},
+			"yt-dlp -o - {url} | mpv -": {"sh", "-c", "yt-dlp -o - 'https://www.youtube.com/watch?v=abc' | mpv -"},
+		}
+		for playerCommand, want := range cases {
+			got := hoop_watcher.NewPlayerOpener("linux", playerCommand).Command(highlightURL).Args
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v for %q, want %v", got, playerCommand, want)
+			}
+		}
+	})
+
+	t.Run("it runs a player directly on Windows", func(t *testing.T) {
+		withTimestamp := mustParseURL(t, "https://www.youtube.com/watch?v=abc&t=42")
+		got := hoop_watcher.NewPlayerOpener("windows", "mpv --fs {url}").Command(withTimestamp).Args
+		want := []string{"mpv", "--fs", "https://www.youtube.com/watch?v=abc&t=42"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("it reads the player from the environment", func(t *testing.T) {
+		t.Setenv(hoop_watcher.PlayerEnv, "mpv {url}")
+		got := hoop_watcher.OpenerFromEnv().Command(highlightURL).Args
+		if got[len(got)-1] != "mpv 'https://www.youtube.com/watch?v=abc'" && got[len(got)-1] != "https://www.youtube.com/watch?v=abc" {
+			t.Errorf("got %v, want the configured player", got)
+		}
+	})
+
+	t.Run("it reports commands that fail", func(t *testing.T) {
+		opener := hoop_watcher.CommandOpener{Name: "false"}
+		if err := opener.Open(highlightURL); err == nil {
+			t.Error("Expected error but err was nil")
+		}
+	})
+}
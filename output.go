@@ -0,0 +1,137 @@
+package hoop_watcher
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type OutputFormat string
+
+const (
+	OutputText OutputFormat = "text"
+	OutputJSON OutputFormat = "json"
+	OutputCSV  OutputFormat = "csv"
+	OutputTSV  OutputFormat = "tsv"
+	OutputM3U  OutputFormat = "m3u"
+)
+
+func ParseOutputFormat(formatStr string) (OutputFormat, error) {
+	if formatStr == "" {
+		return OutputText, nil
+	}
+	switch format := OutputFormat(strings.ToLower(formatStr)); format {
+	case OutputText, OutputJSON, OutputCSV, OutputTSV, OutputM3U:
+		return format, nil
+	}
+	return OutputText, fmt.Errorf("Invalid output format %q, expected one of text, json, csv, tsv or m3u", formatStr)
+}
+
+// highlightRecordHeader names the columns of CSV and TSV output, matching the
+// keys of the JSON output.
+var highlightRecordHeader = []string{
+	"title",
+	"url",
+	"channel",
+	"channel_id",
+	"trusted",
+	"published_at",
+	"duration_seconds",
+	"view_count",
+	"score",
+	"thumbnail_url",
+	"definition",
+}
+
+func highlightRecord(highlight Highlight) []string {
+	publishedAt := ""
+	if !highlight.PublishedAt.IsZero() {
+		publishedAt = highlight.PublishedAt.Format(time.RFC3339)
+	}
+	return []string{
+		highlight.Title,
+		highlight.URL.String(),
+		highlight.Channel,
+		highlight.ChannelId,
+		strconv.FormatBool(highlight.Trusted),
+		publishedAt,
+		strconv.FormatInt(int64(highlight.Duration/time.Second), 10),
+		strconv.FormatUint(highlight.ViewCount, 10),
+		strconv.Itoa(highlight.Score),
+		highlight.ThumbnailURL,
+		highlight.Definition,
+	}
+}
+
+func writeHighlightRecords(out io.Writer, highlights []Highlight, separator rune) error {
+	w := csv.NewWriter(out)
+	w.Comma = separator
+	if err := w.Write(highlightRecordHeader); err != nil {
+		return err
+	}
+	for _, highlight := range highlights {
+		if err := w.Write(highlightRecord(highlight)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func writeHighlightsM3U(out io.Writer, highlights []Highlight) error {
+	if _, err := fmt.Fprintln(out, "#EXTM3U"); err != nil {
+		return err
+	}
+	for _, highlight := range highlights {
+		seconds := int64(highlight.Duration / time.Second)
+		if seconds == 0 {
+			seconds = -1
+		}
+		if _, err := fmt.Fprintf(out, "#EXTINF:%d,%s - %s\n%s\n", seconds, highlight.Channel, highlight.Title, highlight.URL.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHighlightsText(out io.Writer, highlights []Highlight) error {
+	for i, highlight := range highlights {
+		if _, err := fmt.Fprintf(
+			out,
+			"[%d] %s | %s | %s | %s views | %s\n",
+			i+1,
+			highlight.Channel,
+			highlight.Title,
+			FormatDuration(highlight.Duration),
+			FormatViewCount(highlight.ViewCount),
+			FormatPublishedAt(highlight.PublishedAt),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteHighlights renders highlights in format. The JSON output is the same
+// as the server's highlights response.
+func WriteHighlights(out io.Writer, highlights []Highlight, format OutputFormat) error {
+	switch format {
+	case OutputJSON:
+		if highlights == nil {
+			highlights = []Highlight{}
+		}
+		return json.NewEncoder(out).Encode(highlights)
+	case OutputCSV:
+		return writeHighlightRecords(out, highlights, ',')
+	case OutputTSV:
+		return writeHighlightRecords(out, highlights, '\t')
+	case OutputM3U:
+		return writeHighlightsM3U(out, highlights)
+	default:
+		return writeHighlightsText(out, highlights)
+	}
+}
@@ -0,0 +1,88 @@
+package hoop_watcher_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+func TestWriteHighlights(t *testing.T) {
+	highlights := []hoop_watcher.Highlight{
+		{
+			Title:       "Knicks vs Heat, Full Game Highlights",
+			URL:         mustParseURL(t, "https://www.youtube.com/watch?v=abc"),
+			Channel:     "NBA",
+			ChannelId:   "UCWJ2lWNubArHWmf3FIHbfcQ",
+			Trusted:     true,
+			PublishedAt: time.Date(2023, time.January, 2, 4, 0, 0, 0, time.UTC),
+			Duration:    10*time.Minute + 32*time.Second,
+			ViewCount:   1000,
+			Score:       30,
+		},
+	}
+
+	t.Run("it writes JSON the server can read", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		if err := hoop_watcher.WriteHighlights(out, highlights, hoop_watcher.OutputJSON); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		var got []hoop_watcher.Highlight
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if !reflect.DeepEqual(got, highlights) {
+			t.Errorf("got %v, want %v", got, highlights)
+		}
+	})
+
+	t.Run("it writes an empty JSON list without highlights", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		if err := hoop_watcher.WriteHighlights(out, nil, hoop_watcher.OutputJSON); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if out.String() != "[]\n" {
+			t.Errorf("got %q, want an empty list", out.String())
+		}
+	})
+
+	t.Run("it writes delimited records", func(t *testing.T) {
+		cases := map[hoop_watcher.OutputFormat]string{
+			hoop_watcher.OutputCSV: "title,url,channel,channel_id,trusted,published_at,duration_seconds,view_count,score,thumbnail_url,definition\n" +
+				"\"Knicks vs Heat, Full Game Highlights\",https://www.youtube.com/watch?v=abc,NBA,UCWJ2lWNubArHWmf3FIHbfcQ,true,2023-01-02T04:00:00Z,632,1000,30,,\n",
+			hoop_watcher.OutputTSV: "title\turl\tchannel\tchannel_id\ttrusted\tpublished_at\tduration_seconds\tview_count\tscore\tthumbnail_url\tdefinition\n" +
+				"Knicks vs Heat, Full Game Highlights\thttps://www.youtube.com/watch?v=abc\tNBA\tUCWJ2lWNubArHWmf3FIHbfcQ\ttrue\t2023-01-02T04:00:00Z\t632\t1000\t30\t\t\n",
+		}
+		for format, want := range cases {
+			out := &bytes.Buffer{}
+			if err := hoop_watcher.WriteHighlights(out, highlights, format); err != nil {
+				t.Fatalf("Found err: %v", err)
+			}
+			if out.String() != want {
+				t.Errorf("got %q, want %q", out.String(), want)
+			}
+		}
+	})
+
+	t.Run("it writes an M3U playlist", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		if err := hoop_watcher.WriteHighlights(out, highlights, hoop_watcher.OutputM3U); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		want := "#EXTM3U\n#EXTINF:632,NBA - Knicks vs Heat, Full Game Highlights\nhttps://www.youtube.com/watch?v=abc\n"
+		if out.String() != want {
+			t.Errorf("got %q, want %q", out.String(), want)
+		}
+	})
+
+	t.Run("it rejects unknown formats", func(t *testing.T) {
+		_, err := hoop_watcher.ParseOutputFormat("xml")
+		if err == nil || !strings.Contains(err.Error(), "Invalid output format") {
+			t.Errorf("got err %v, want an invalid output format error", err)
+		}
+	})
+}
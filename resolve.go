@@ -0,0 +1,156 @@
+package hoop_watcher
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// Scores given to a team by how closely a query matches it, from 0 to 1.
+const (
+	exactTeamMatchScore     = 1.0
+	queryNamesTeamScore     = 0.9
+	exactCityMatchScore     = 0.8
+	queryNamesCityScore     = 0.75
+	wordTeamMatchScore      = 0.7
+	prefixTeamMatchScore    = 0.6
+	maxFuzzyTeamMatchScore  = 0.5
+	TeamMatchThreshold      = 0.3
+	TeamAmbiguityMargin     = 0.15
+	maxAmbiguousSuggestions = 3
+)
+
+// ErrAmbiguousTeam is returned when a query matches several teams about as
+// well, e.g. "LA".
+var ErrAmbiguousTeam = errors.New("Ambiguous team")
+
+// AmbiguousTeamError holds the teams a query could mean, best match first.
+type AmbiguousTeamError struct {
+	Query      string
+	Candidates []TeamCandidate
+}
+
+func (e *AmbiguousTeamError) Error() string {
+	names := []string{}
+	for _, candidate := range e.Candidates {
+		names = append(names, candidate.Team.FullName)
+	}
+	return fmt.Sprintf("%q is ambiguous, did you mean %s?", e.Query, joinChoices(names))
+}
+
+func (e *AmbiguousTeamError) Unwrap() error {
+	return ErrAmbiguousTeam
+}
+
+// joinChoices joins names as "A, B or C".
+func joinChoices(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// TeamCandidate is a team a query may refer to and how confident the match
+// is, from 0 to 1.
+type TeamCandidate struct {
+	Team  NBATeam `json:"team"`
+	Score float64 `json:"score"`
+}
+
+// fuzzyTeamMatchScore scores query as a subsequence of token, scaled by how
+// much of token it covers.
+func fuzzyTeamMatchScore(query string, token string) float64 {
+	if !fuzzy.MatchNormalizedFold(query, token) {
+		return 0
+	}
+	return maxFuzzyTeamMatchScore * float64(len(query)) / float64(len(token))
+}
+
+// scoreTeamMatch scores how well query refers to team.
+func scoreTeamMatch(query string, team NBATeam) float64 {
+	teamMatchTokens := getTeamMatchTokens(team)
+	lowerCaseTeamName, lowerCaseTeamAbbreviation, teamCity, shortenedTeamName := teamMatchTokens[0], teamMatchTokens[1], teamMatchTokens[2], teamMatchTokens[3]
+	nameTokens := append([]string{lowerCaseTeamName, shortenedTeamName}, teamAliases(team)...)
+
+	best := 0.0
+	score := func(s float64) {
+		best = max(best, s)
+	}
+	if query == lowerCaseTeamAbbreviation {
+		score(exactTeamMatchScore)
+	}
+	for _, token := range append(nameTokens, teamCity) {
+		if token == "" {
+			continue
+		}
+		isCity := token == teamCity
+		switch {
+		case query == token && isCity:
+			score(exactCityMatchScore)
+		case query == token:
+			score(exactTeamMatchScore)
+		case containsWord(query, token) && isCity:
+			score(queryNamesCityScore)
+		case containsWord(query, token):
+			score(queryNamesTeamScore)
+		case containsWord(token, query):
+			score(wordTeamMatchScore)
+		case containsWordPrefix(token, query):
+			score(prefixTeamMatchScore)
+		default:
+			score(fuzzyTeamMatchScore(query, token))
+		}
+	}
+	if lowerCaseTeamAbbreviation != "" {
+		score(fuzzyTeamMatchScore(query, lowerCaseTeamAbbreviation))
+	}
+	return best
+}
+
+// RankTeams scores every team against query and returns the ones that match
+// at all, best first. Ties keep the order of nbaTeams.
+func RankTeams(query string, nbaTeams []NBATeam) []TeamCandidate {
+	lowerCaseQuery := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	candidates := []TeamCandidate{}
+	if lowerCaseQuery == "" {
+		return candidates
+	}
+	for _, team := range nbaTeams {
+		if score := scoreTeamMatch(lowerCaseQuery, team); score > 0 {
+			candidates = append(candidates, TeamCandidate{Team: team, Score: score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// PickTeam returns the team query refers to. Returns ErrTeamNotFound if no
+// team scores above TeamMatchThreshold, and an *AmbiguousTeamError if others
+// score within TeamAmbiguityMargin of the best.
+func PickTeam(query string, nbaTeams []NBATeam) (NBATeam, error) {
+	candidates := []TeamCandidate{}
+	for _, candidate := range RankTeams(query, nbaTeams) {
+		if candidate.Score >= TeamMatchThreshold {
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) == 0 {
+		return NBATeam{}, fmt.Errorf("%w: %s", ErrTeamNotFound, strings.TrimSpace(query))
+	}
+
+	closeCalls := candidates[:1]
+	for _, candidate := range candidates[1:] {
+		if candidates[0].Score-candidate.Score < TeamAmbiguityMargin && len(closeCalls) < maxAmbiguousSuggestions {
+			closeCalls = append(closeCalls, candidate)
+		}
+	}
+	if len(closeCalls) > 1 {
+		return NBATeam{}, &AmbiguousTeamError{Query: strings.TrimSpace(query), Candidates: closeCalls}
+	}
+	return candidates[0].Team, nil
+}
@@ -0,0 +1,79 @@
+package hoop_watcher_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+func TestRankTeams(t *testing.T) {
+	teams := mustLoadTeams(t)
+
+	t.Run("it ranks exact matches first", func(t *testing.T) {
+		got := hoop_watcher.RankTeams("Sixers", teams)
+		if len(got) == 0 || got[0].Team.Abbreviation != "PHI" || got[0].Score != 1 {
+			t.Errorf("got %v, want the 76ers with a score of 1", got)
+		}
+	})
+
+	t.Run("it ranks ties the same way every time", func(t *testing.T) {
+		want := hoop_watcher.RankTeams("new", teams)
+		for i := 0; i < 10; i++ {
+			if got := hoop_watcher.RankTeams("new", teams); !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+		}
+	})
+
+	t.Run("it returns nothing for a query that matches no team", func(t *testing.T) {
+		if got := hoop_watcher.RankTeams("zzqqxx", teams); len(got) != 0 {
+			t.Errorf("got %v, want no candidates", got)
+		}
+		if got := hoop_watcher.FuzzyGetTeamFromQuery("zzqqxx", teams); got != nil {
+			t.Errorf("got %v, want nil", got)
+		}
+	})
+}
+
+func TestPickTeam(t *testing.T) {
+	teams := mustLoadTeams(t)
+
+	t.Run("it picks a clear match", func(t *testing.T) {
+		for query, want := range map[string]string{"lakers": "LAL", "la clippers": "LAC", "golden state": "GSW", "dubs": "GSW"} {
+			got, err := hoop_watcher.PickTeam(query, teams)
+			if err != nil {
+				t.Errorf("got err %v for %q", err, query)
+				continue
+			}
+			if got.Abbreviation != want {
+				t.Errorf("got %s for %q, want %s", got.Abbreviation, query, want)
+			}
+		}
+	})
+
+	t.Run("it reports close calls as ambiguous", func(t *testing.T) {
+		_, err := hoop_watcher.PickTeam("LA", teams)
+		var ambiguous *hoop_watcher.AmbiguousTeamError
+		if !errors.As(err, &ambiguous) || !errors.Is(err, hoop_watcher.ErrAmbiguousTeam) {
+			t.Fatalf("got err %v, want an AmbiguousTeamError", err)
+		}
+		got := []string{}
+		for _, candidate := range ambiguous.Candidates {
+			got = append(got, candidate.Team.Abbreviation)
+		}
+		if want := []string{"LAC", "LAL"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got candidates %v, want %v", got, want)
+		}
+		if want := `"LA" is ambiguous, did you mean LA Clippers or Los Angeles Lakers?`; err.Error() != want {
+			t.Errorf("got %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("it returns ErrTeamNotFound below the threshold", func(t *testing.T) {
+		if _, err := hoop_watcher.PickTeam("supersonics", teams); !errors.Is(err, hoop_watcher.ErrTeamNotFound) {
+			t.Errorf("got err %v, want %v", err, hoop_watcher.ErrTeamNotFound)
+		}
+	})
+}
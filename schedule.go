@@ -0,0 +1,151 @@
+package hoop_watcher
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	_ "time/tzdata"
+)
+
+// ScheduleTipOffLocation is the time zone tip-off times in schedule files are
+// written in.
+const ScheduleTipOffLocation = "America/New_York"
+
+const scheduleTipOffFormat = "15:04"
+
+// ScheduleEntry is one game of a season schedule file.
+type ScheduleEntry struct {
+	Date   string `json:"date"`
+	Home   string `json:"home"`
+	Away   string `json:"away"`
+	TipOff string `json:"tip_off"`
+}
+
+// ScheduleStore is where imported games are saved. AddGames saves all of the
+// games or none of them.
+type ScheduleStore interface {
+	GetTeamByAbbrev(abbrev string) (NBATeam, error)
+	AddGames(games []Game) error
+}
+
+func ParseScheduleJSON(r io.Reader) ([]ScheduleEntry, error) {
+	var entries []ScheduleEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ParseScheduleCSV reads a schedule with a date,home,away,tip_off header.
+func ParseScheduleCSV(r io.Reader) ([]ScheduleEntry, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, required := range []string{"date", "home", "away"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("schedule is missing the %s column", required)
+		}
+	}
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	entries := []ScheduleEntry{}
+	for _, record := range records[1:] {
+		entries = append(entries, ScheduleEntry{
+			Date:   field(record, "date"),
+			Home:   field(record, "home"),
+			Away:   field(record, "away"),
+			TipOff: field(record, "tip_off"),
+		})
+	}
+	return entries, nil
+}
+
+func (e ScheduleEntry) toGame(store ScheduleStore, tipOffLocation *time.Location) (Game, error) {
+	date, err := time.Parse(DAILY_DATE_FORMAT, e.Date)
+	if err != nil {
+		return Game{}, fmt.Errorf("invalid date %q", e.Date)
+	}
+	homeTeam, err := store.GetTeamByAbbrev(e.Home)
+	if err != nil {
+		return Game{}, fmt.Errorf("unknown home team %q", e.Home)
+	}
+	awayTeam, err := store.GetTeamByAbbrev(e.Away)
+	if err != nil {
+		return Game{}, fmt.Errorf("unknown away team %q", e.Away)
+	}
+
+	// The status and score are left unset so a re-import keeps the stored ones.
+	game := Game{HomeTeam: homeTeam, AwayTeam: awayTeam, Date: date}
+	if e.TipOff != "" {
+		tipOff, err := time.Parse(scheduleTipOffFormat, e.TipOff)
+		if err != nil {
+			return Game{}, fmt.Errorf("invalid tip-off time %q", e.TipOff)
+		}
+		game.TipOff = time.Date(date.Year(), date.Month(), date.Day(), tipOff.Hour(), tipOff.Minute(), 0, 0, tipOffLocation)
+	}
+	return game, nil
+}
+
+// ImportSchedule saves every game of a schedule, or none of them if an entry
+// cannot be imported. It returns how many games were saved.
+func ImportSchedule(store ScheduleStore, entries []ScheduleEntry) (int, error) {
+	tipOffLocation, err := time.LoadLocation(ScheduleTipOffLocation)
+	if err != nil {
+		return 0, err
+	}
+	games := []Game{}
+	for i, entry := range entries {
+		game, err := entry.toGame(store, tipOffLocation)
+		if err != nil {
+			return 0, fmt.Errorf("schedule entry %d: %w", i+1, err)
+		}
+		games = append(games, game)
+	}
+	if err := store.AddGames(games); err != nil {
+		return 0, err
+	}
+	return len(games), nil
+}
+
+// ImportScheduleFile imports a .json or .csv season schedule.
+func ImportScheduleFile(store ScheduleStore, filePath string) (int, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var entries []ScheduleEntry
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		entries, err = ParseScheduleJSON(f)
+	case ".csv":
+		entries, err = ParseScheduleCSV(f)
+	default:
+		return 0, fmt.Errorf("unsupported schedule file %s, expected .json or .csv", filePath)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return ImportSchedule(store, entries)
+}
@@ -0,0 +1,174 @@
+package hoop_watcher_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+// fakeScheduleStore resolves teams from nba_teams.json and keeps the games it
+// is given.
+type fakeScheduleStore struct {
+	teams []hoop_watcher.NBATeam
+	games []hoop_watcher.Game
+}
+
+func newFakeScheduleStore(t *testing.T) *fakeScheduleStore {
+	t.Helper()
+	return &fakeScheduleStore{teams: mustLoadTeams(t)}
+}
+
+func (s *fakeScheduleStore) GetTeamByAbbrev(abbrev string) (hoop_watcher.NBATeam, error) {
+	for _, team := range s.teams {
+		if strings.EqualFold(team.Abbreviation, abbrev) {
+			return team, nil
+		}
+	}
+	return hoop_watcher.NBATeam{}, errors.New("no such team")
+}
+
+func (s *fakeScheduleStore) AddGames(games []hoop_watcher.Game) error {
+	for _, game := range games {
+		game.Id = len(s.games) + 1
+		s.games = append(s.games, game)
+	}
+	return nil
+}
+
+func writeScheduleFile(t *testing.T, name string, contents string) string {
+	t.Helper()
+	filePath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filePath, []byte(contents), 0o644); err != nil {
+		t.Fatalf("could not write schedule file: %v", err)
+	}
+	return filePath
+}
+
+func TestImportSchedule(t *testing.T) {
+	openingNight := time.Date(2023, time.October, 24, 0, 0, 0, 0, time.UTC)
+
+	t.Run("it imports a JSON schedule", func(t *testing.T) {
+		store := newFakeScheduleStore(t)
+		filePath := writeScheduleFile(t, "schedule.json", `[
+			{"date": "2023-10-24", "home": "DEN", "away": "LAL", "tip_off": "19:30"},
+			{"date": "2023-10-24", "home": "gsw", "away": "phx", "tip_off": "22:00"}
+		]`)
+
+		imported, err := hoop_watcher.ImportScheduleFile(store, filePath)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if imported != 2 || len(store.games) != 2 {
+			t.Fatalf("got %d imported games %v, want 2", imported, store.games)
+		}
+
+		games := store.games
+		if games[0].HomeTeam.Abbreviation != "DEN" || games[0].AwayTeam.Abbreviation != "LAL" || !games[0].Date.Equal(openingNight) {
+			t.Errorf("got %v, want the Lakers at the Nuggets on opening night first", games[0])
+		}
+		if games[1].HomeTeam.Abbreviation != "GSW" || games[1].AwayTeam.Abbreviation != "PHX" {
+			t.Errorf("got %v, want the Suns at the Warriors", games[1])
+		}
+		if want := time.Date(2023, time.October, 24, 23, 30, 0, 0, time.UTC); !games[0].TipOff.Equal(want) {
+			t.Errorf("got tip-off %v, want %v", games[0].TipOff, want)
+		}
+	})
+
+	t.Run("it imports a CSV schedule", func(t *testing.T) {
+		store := newFakeScheduleStore(t)
+		filePath := writeScheduleFile(t, "schedule.csv", "date,home,away,tip_off\n2023-10-24,DEN,LAL,19:30\n2023-10-25,NYK,BOS,\n")
+
+		imported, err := hoop_watcher.ImportScheduleFile(store, filePath)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if imported != 2 || len(store.games) != 2 {
+			t.Fatalf("got %d imported games %v, want 2", imported, store.games)
+		}
+
+		game := store.games[1]
+		if game.HomeTeam.Abbreviation != "NYK" || !game.Date.Equal(openingNight.AddDate(0, 0, 1)) || !game.TipOff.IsZero() {
+			t.Errorf("got %v, want the Celtics at the Knicks without a tip-off", game)
+		}
+	})
+
+	t.Run("it imports into the SQLite DB", func(t *testing.T) {
+		db := newTestDB(t)
+		filePath := writeScheduleFile(t, "schedule.csv", "date,home,away\n2023-10-24,DEN,LAL\n")
+
+		if _, err := hoop_watcher.ImportScheduleFile(db, filePath); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		games, err := db.GetGamesByDate(openingNight)
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		if len(games) != 1 || games[0].HomeTeam.FullName != "Denver Nuggets" || games[0].AwayTeam.FullName != "Los Angeles Lakers" {
+			t.Errorf("got %v, want the Lakers at the Nuggets", games)
+		}
+	})
+
+	t.Run("it reports unknown teams", func(t *testing.T) {
+		store := newFakeScheduleStore(t)
+		filePath := writeScheduleFile(t, "schedule.csv", "date,home,away\n2023-10-24,DEN,LAL\n2023-10-24,SEA,POR\n")
+
+		imported, err := hoop_watcher.ImportScheduleFile(store, filePath)
+		if err == nil {
+			t.Fatal("Expected error but err was nil")
+		}
+		if imported != 0 || len(store.games) != 0 || !strings.Contains(err.Error(), `schedule entry 2: unknown home team "SEA"`) {
+			t.Errorf("got %d imported games %v and err %v, want none imported", imported, store.games, err)
+		}
+	})
+
+	t.Run("it imports nothing into the SQLite DB from a schedule with a bad entry", func(t *testing.T) {
+		db := newTestDB(t)
+		filePath := writeScheduleFile(t, "schedule.csv", "date,home,away\n2023-10-24,DEN,LAL\n2023-10-24,SEA,POR\n")
+
+		if _, err := hoop_watcher.ImportScheduleFile(db, filePath); err == nil {
+			t.Fatal("Expected error but err was nil")
+		}
+		games, err := db.GetGamesByDate(openingNight)
+		if err != nil || len(games) != 0 {
+			t.Errorf("got %v, %v, want no games", games, err)
+		}
+	})
+
+	t.Run("it keeps the score and status of a game it imports again", func(t *testing.T) {
+		db := newTestDB(t)
+		nuggets, _ := db.GetTeamByAbbrev("DEN")
+		lakers, _ := db.GetTeamByAbbrev("LAL")
+		final := hoop_watcher.Game{HomeTeam: nuggets, AwayTeam: lakers, Date: openingNight, HomeScore: 119, AwayScore: 107, Status: hoop_watcher.GameFinal}
+		if _, err := db.AddGame(final); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		filePath := writeScheduleFile(t, "schedule.csv", "date,home,away,tip_off\n2023-10-24,DEN,LAL,20:30\n")
+
+		if _, err := hoop_watcher.ImportScheduleFile(db, filePath); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+		games, err := db.GetGamesByDate(openingNight)
+		if err != nil || len(games) != 1 {
+			t.Fatalf("got %v, %v, want the one game", games, err)
+		}
+		game := games[0]
+		if game.Status != hoop_watcher.GameFinal || game.HomeScore != 119 || game.AwayScore != 107 {
+			t.Errorf("got %v, want the final score kept", game)
+		}
+		if want := time.Date(2023, time.October, 25, 0, 30, 0, 0, time.UTC); !game.TipOff.Equal(want) {
+			t.Errorf("got tip-off %v, want %v", game.TipOff, want)
+		}
+	})
+
+	t.Run("it rejects other file types", func(t *testing.T) {
+		filePath := writeScheduleFile(t, "schedule.txt", "")
+		if _, err := hoop_watcher.ImportScheduleFile(newFakeScheduleStore(t), filePath); err == nil {
+			t.Error("Expected error but err was nil")
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package hoop_watcher
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	teamMentionScore      = 10
+	missingTeamScore      = -10
+	highlightKeywordScore = 5
+	titleDateScore        = 5
+	fullGameKeywordScore  = 3
+	trustedChannelScore   = 8
+	negativeTermScore     = -30
+)
+
+// TrustedChannels are channel titles known to post real game highlights.
+var TrustedChannels = []string{
+	"NBA",
+	"ESPN",
+	"House of Highlights",
+	"Bleacher Report",
+	"MLG Highlights",
+}
+
+var fullGameKeywords = []string{
+	"full game",
+	"game recap",
+	"condensed",
+	"extended highlights",
+}
+
+var negativeTerms = []string{
+	"react",
+	"fantasy",
+	"2k",
+	"simulation",
+	"prediction",
+	"preview",
+	"press conference",
+	"postgame",
+	"podcast",
+	"live stream",
+	"betting",
+}
+
+// titleDateFormats are the ways highlight titles commonly write a game date.
+var titleDateFormats = []string{
+	HUMAN_DATE_FORMAT,
+	"Jan 2, 2006",
+	"January 2",
+	"Jan 2",
+	"1/2/2006",
+	"1/2/06",
+	"1.2.2006",
+	DAILY_DATE_FORMAT,
+}
+
+// isWordByte reports whether b is an ASCII letter, digit or underscore, the
+// bytes \w matches in a regexp.
+func isWordByte(b byte) bool {
+	return b == '_' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+// isWordBoundary reports whether i is between a word byte and another byte of
+// text, where \b matches in a regexp.
+func isWordBoundary(text string, i int) bool {
+	before := i > 0 && isWordByte(text[i-1])
+	after := i < len(text) && isWordByte(text[i])
+	return before != after
+}
+
+// containsMatch reports whether substr occurs in text at a start for which
+// match is true.
+func containsMatch(text string, substr string, match func(start int) bool) bool {
+	for offset := 0; offset <= len(text); {
+		i := strings.Index(text[offset:], substr)
+		if i < 0 {
+			return false
+		}
+		if match(offset + i) {
+			return true
+		}
+		offset += i + 1
+	}
+	return false
+}
+
+// containsWord reports whether text has word with a word boundary on either
+// side, so "nyk" matches "nyk @ mia" but not "nykaa". Scoring calls it for
+// every title and team token, so it avoids compiling a regexp each time.
+func containsWord(text string, word string) bool {
+	return containsMatch(text, word, func(start int) bool {
+		return isWordBoundary(text, start) && isWordBoundary(text, start+len(word))
+	})
+}
+
+// containsWordPrefix reports whether a word in text starts with prefix, so
+// "2k" matches "NBA 2K24" and "react" matches "reaction".
+func containsWordPrefix(text string, prefix string) bool {
+	return containsMatch(text, prefix, func(start int) bool {
+		return isWordBoundary(text, start)
+	})
+}
+
+func titleMentionsTeam(title string, team NBATeam) bool {
+	teamMatchTokens := getTeamMatchTokens(team)
+	lowerCaseFullName, lowerCaseAbbreviation, teamCity, shortenedTeamName := teamMatchTokens[0], teamMatchTokens[1], teamMatchTokens[2], teamMatchTokens[3]
+	lowerCaseName := strings.ToLower(team.Name)
+	return (lowerCaseFullName != "" && strings.Contains(title, lowerCaseFullName)) ||
+		(lowerCaseName != "" && strings.Contains(title, lowerCaseName)) ||
+		(teamCity != "" && strings.Contains(title, teamCity)) ||
+		(shortenedTeamName != "" && strings.Contains(title, shortenedTeamName)) ||
+		(lowerCaseAbbreviation != "" && containsWord(title, lowerCaseAbbreviation)) ||
+		slices.ContainsFunc(teamAliases(team), func(alias string) bool { return containsWord(title, alias) })
+}
+
+func titleMentionsDate(title string, date time.Time) bool {
+	for _, format := range titleDateFormats {
+		if strings.Contains(title, strings.ToLower(date.Format(format))) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTrustedChannel(channel string) bool {
+	for _, trustedChannel := range TrustedChannels {
+		if strings.EqualFold(channel, trustedChannel) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScoreHighlight rates how likely highlight is the highlight video of the
+// game between teams on date. A zero date skips the date check.
+func ScoreHighlight(highlight Highlight, teams []NBATeam, date time.Time) int {
+	title := strings.ToLower(highlight.Title)
+	score := 0
+	for _, team := range teams {
+		if titleMentionsTeam(title, team) {
+			score += teamMentionScore
+		} else {
+			score += missingTeamScore
+		}
+	}
+	if strings.Contains(title, "highlights") {
+		score += highlightKeywordScore
+	}
+	if !date.IsZero() && titleMentionsDate(title, date) {
+		score += titleDateScore
+	}
+	for _, keyword := range fullGameKeywords {
+		if strings.Contains(title, keyword) {
+			score += fullGameKeywordScore
+			break
+		}
+	}
+	if highlight.Trusted || isTrustedChannel(highlight.Channel) {
+		score += trustedChannelScore
+	}
+	for _, term := range negativeTerms {
+		if containsWordPrefix(title, term) {
+			score += negativeTermScore
+		}
+	}
+	return score
+}
+
+// RankHighlights scores each highlight and sorts them best match first.
+func RankHighlights(highlights []Highlight, teams []NBATeam, date time.Time) []Highlight {
+	ranked := make([]Highlight, len(highlights))
+	for i, highlight := range highlights {
+		highlight.Score = ScoreHighlight(highlight, teams, date)
+		ranked[i] = highlight
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
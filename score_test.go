@@ -0,0 +1,66 @@
+package hoop_watcher_test
+
+import (
+	"testing"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+)
+
+func TestRankHighlights(t *testing.T) {
+	knicks := hoop_watcher.NBATeam{Name: "Knicks", FullName: "New York Knicks", Abbreviation: "NYK"}
+	heat := hoop_watcher.NBATeam{Name: "Heat", FullName: "Miami Heat", Abbreviation: "MIA"}
+	teams := []hoop_watcher.NBATeam{knicks, heat}
+	gameDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("it puts the best match first", func(t *testing.T) {
+		highlights := []hoop_watcher.Highlight{
+			{Title: "Knicks vs Heat REACTION | NBA 2K24"},
+			{Title: "Knicks Highlights", Channel: "Some Reuploader"},
+			{Title: "KNICKS at HEAT | FULL GAME HIGHLIGHTS | January 1, 2023", Channel: "NBA"},
+			{Title: "Knicks vs Heat Full Game Highlights", Channel: "Some Reuploader"},
+		}
+		got := hoop_watcher.RankHighlights(highlights, teams, gameDate)
+
+		wantOrder := []string{
+			"KNICKS at HEAT | FULL GAME HIGHLIGHTS | January 1, 2023",
+			"Knicks vs Heat Full Game Highlights",
+			"Knicks Highlights",
+			"Knicks vs Heat REACTION | NBA 2K24",
+		}
+		for i, want := range wantOrder {
+			if got[i].Title != want {
+				t.Errorf("got %s at position %d, want %s", got[i].Title, i, want)
+			}
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i-1].Score < got[i].Score {
+				t.Errorf("scores not sorted: %d before %d", got[i-1].Score, got[i].Score)
+			}
+		}
+	})
+
+	t.Run("it penalizes fantasy and reaction videos below zero", func(t *testing.T) {
+		cases := []string{
+			"Knicks vs Heat fantasy basketball preview",
+			"Knicks Heat highlights reaction",
+			"Knicks vs Heat NBA 2K24 simulation",
+		}
+		for _, title := range cases {
+			got := hoop_watcher.ScoreHighlight(hoop_watcher.Highlight{Title: title}, teams, gameDate)
+			if got > 0 {
+				t.Errorf("got score %d for %q, want at most 0", got, title)
+			}
+		}
+	})
+
+	t.Run("it matches abbreviations only as whole words", func(t *testing.T) {
+		nuggets := hoop_watcher.NBATeam{Name: "Nuggets", FullName: "Denver Nuggets", Abbreviation: "DEN"}
+		warriors := hoop_watcher.NBATeam{Name: "Warriors", FullName: "Golden State Warriors", Abbreviation: "GSW"}
+		got := hoop_watcher.ScoreHighlight(hoop_watcher.Highlight{Title: "Golden State Highlights"}, []hoop_watcher.NBATeam{nuggets}, time.Time{})
+		want := hoop_watcher.ScoreHighlight(hoop_watcher.Highlight{Title: "Golden State Highlights"}, []hoop_watcher.NBATeam{warriors}, time.Time{})
+		if got >= want {
+			t.Errorf("got score %d for the Nuggets, want less than the Warriors' %d", got, want)
+		}
+	})
+}
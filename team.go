@@ -1,12 +1,13 @@
 package hoop_watcher
 
 import (
+	_ "embed"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
-
-	"github.com/lithammer/fuzzysearch/fuzzy"
 )
 
 type NBATeam struct {
@@ -21,38 +22,102 @@ type NBATeam struct {
 
 const TeamFileName = "nba_teams.json"
 
-const teamFileLoadErrorMessage = "Error occurred loading NBA teams"
-
-func GetNBATeamsFromJSON(filePath string) []NBATeam {
+// GetNBATeamsFromJSON loads the teams from the JSON file at filePath. Returns
+// ErrInvalidTeamsFile if it cannot be read or holds no teams.
+func GetNBATeamsFromJSON(filePath string) ([]NBATeam, error) {
 	f, err := os.Open(filePath)
 	if err != nil {
-		log.Fatal(teamFileLoadErrorMessage)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTeamsFile, err)
 	}
+	defer f.Close()
 
 	var nbaTeams []NBATeam
-	err = json.NewDecoder(f).Decode(&nbaTeams)
-	f.Close()
-	if err != nil {
-		log.Fatal(teamFileLoadErrorMessage)
+	if err := json.NewDecoder(f).Decode(&nbaTeams); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTeamsFile, err)
 	}
+	if len(nbaTeams) == 0 {
+		return nil, fmt.Errorf("%w: no teams in %s", ErrInvalidTeamsFile, filePath)
+	}
+
+	return nbaTeams, nil
+}
 
-	return nbaTeams
+const TeamAliasFileName = "nba_team_aliases.json"
+
+//go:embed nba_team_aliases.json
+var teamAliasesJSON []byte
+
+// TeamAliases maps a team's abbreviation to the other names fans and video
+// titles use for it: nicknames, former abbreviations and multi-word mascots.
+type TeamAliases map[string][]string
+
+// DefaultTeamAliases are the aliases in nba_team_aliases.json.
+var DefaultTeamAliases = mustParseTeamAliases(teamAliasesJSON)
+
+func mustParseTeamAliases(data []byte) TeamAliases {
+	var aliases TeamAliases
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		panic(fmt.Sprintf("invalid %s: %v", TeamAliasFileName, err))
+	}
+	return aliases
+}
+
+// teamAliases returns the lower case aliases of team.
+func teamAliases(team NBATeam) []string {
+	aliases := []string{}
+	for _, alias := range DefaultTeamAliases[team.Abbreviation] {
+		aliases = append(aliases, strings.ToLower(alias))
+	}
+	return aliases
+}
+
+// TeamLookup finds a single team, returning ErrTeamNotFound if there is no
+// match.
+type TeamLookup interface {
+	GetTeamByID(id int) (NBATeam, error)
+	GetTeamByAbbrev(abbrev string) (NBATeam, error)
+	GetTeamByName(name string) (NBATeam, error)
+}
+
+// ResolveTeam finds the team query refers to, which can be a team id,
+// abbreviation, name, full name or alias.
+func ResolveTeam(lookup TeamLookup, query string) (NBATeam, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return NBATeam{}, fmt.Errorf("%w: empty query", ErrTeamNotFound)
+	}
+	if id, err := strconv.Atoi(query); err == nil {
+		return lookup.GetTeamByID(id)
+	}
+	team, err := lookup.GetTeamByAbbrev(query)
+	if !errors.Is(err, ErrTeamNotFound) {
+		return team, err
+	}
+	return lookup.GetTeamByName(query)
 }
 
-func GetNBATeamsFromDB(db HoopWatcherDB) []NBATeam {
+func GetNBATeamsFromDB(db HoopWatcherDB) ([]NBATeam, error) {
 	nbaTeams, err := db.GetAllTeams()
 	if err != nil {
-		log.Fatal("Error occurred getting NBA teams from database")
+		return nil, fmt.Errorf("could not load NBA teams: %w", err)
 	}
-	return nbaTeams
+	return nbaTeams, nil
 }
 
+// getTeamMatchTokens returns the lower case full name, abbreviation, city and
+// name of team. City and name fall back to splitting the full name when unset.
 func getTeamMatchTokens(team NBATeam) (teamToMatchTokens []string) {
 	lowerCaseTeamName := strings.ToLower(team.FullName)
 	lowerCaseTeamAbbreviation := strings.ToLower(team.Abbreviation)
+	teamCity := strings.ToLower(team.City)
+	shortenedTeamName := strings.ToLower(team.Name)
 	splitTeamName := strings.Split(lowerCaseTeamName, " ")
-	teamCity := strings.Join(splitTeamName[:len(splitTeamName)-1], " ")
-	shortenedTeamName := splitTeamName[len(splitTeamName)-1]
+	if teamCity == "" {
+		teamCity = strings.Join(splitTeamName[:len(splitTeamName)-1], " ")
+	}
+	if shortenedTeamName == "" {
+		shortenedTeamName = splitTeamName[len(splitTeamName)-1]
+	}
 	return []string{
 		lowerCaseTeamName,
 		lowerCaseTeamAbbreviation,
@@ -61,41 +126,52 @@ func getTeamMatchTokens(team NBATeam) (teamToMatchTokens []string) {
 	}
 }
 
-func queryMatchesTeam(query string, team NBATeam) bool {
-	lowerCaseQuery := strings.ToLower(query)
-	lowerCaseTeamName := strings.ToLower(team.FullName)
-	lowerCaseTeamAbbreviation := strings.ToLower(team.Abbreviation)
-	if strings.Contains(lowerCaseQuery, lowerCaseTeamName) || lowerCaseQuery == lowerCaseTeamAbbreviation {
+// queryNamesTeam reports whether query mentions team by its full name, name or
+// an alias, or is its abbreviation.
+func queryNamesTeam(query string, team NBATeam) bool {
+	lowerCaseQuery := strings.ToLower(strings.TrimSpace(query))
+	teamMatchTokens := getTeamMatchTokens(team)
+	lowerCaseTeamName, lowerCaseTeamAbbreviation, shortenedTeamName := teamMatchTokens[0], teamMatchTokens[1], teamMatchTokens[3]
+	if (lowerCaseTeamName != "" && strings.Contains(lowerCaseQuery, lowerCaseTeamName)) || lowerCaseQuery == lowerCaseTeamAbbreviation {
 		return true
 	}
-
-	splitTeamName := strings.Split(lowerCaseTeamName, " ")
-	teamCity := strings.Join(splitTeamName[:len(splitTeamName)-1], " ")
-	shortenedTeamName := splitTeamName[len(splitTeamName)-1]
-	if strings.Contains(lowerCaseQuery, teamCity) || strings.Contains(lowerCaseQuery, shortenedTeamName) {
+	if shortenedTeamName != "" && containsWord(lowerCaseQuery, shortenedTeamName) {
 		return true
 	}
+	for _, alias := range teamAliases(team) {
+		if containsWord(lowerCaseQuery, alias) {
+			return true
+		}
+	}
 	return false
 }
 
+func queryMatchesTeam(query string, team NBATeam) bool {
+	if queryNamesTeam(query, team) {
+		return true
+	}
+	teamCity := getTeamMatchTokens(team)[2]
+	return teamCity != "" && containsWord(strings.ToLower(query), teamCity)
+}
+
+// FuzzyGetTeamFromQuery returns the best ranked team for query, or nil if no
+// team matches it at all.
 func FuzzyGetTeamFromQuery(query string, nbaTeams []NBATeam) *NBATeam {
-	matchTokensByTeam := map[NBATeam][]string{}
-	for _, team := range nbaTeams {
-		matchTokensByTeam[team] = getTeamMatchTokens(team)
-	}
-	var closestTeam NBATeam
-	maxMatches := 0
-	for team, matchTokens := range matchTokensByTeam {
-		matches := len(fuzzy.FindNormalizedFold(query, matchTokens))
-		if matches > maxMatches {
-			closestTeam = team
-			maxMatches = matches
-		}
+	candidates := RankTeams(query, nbaTeams)
+	if len(candidates) == 0 {
+		return nil
 	}
-	return &closestTeam
+	return &candidates[0].Team
 }
 
+// GetTeamFromQuery finds the team query mentions. Names and aliases are
+// checked before cities, so "LA Lakers" is not taken for the LA Clippers.
 func GetTeamFromQuery(query string, nbaTeams []NBATeam) *NBATeam {
+	for _, team := range nbaTeams {
+		if queryNamesTeam(query, team) {
+			return &team
+		}
+	}
 	for _, team := range nbaTeams {
 		if queryMatchesTeam(query, team) {
 			return &team
@@ -1,6 +1,10 @@
 package hoop_watcher_test
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
@@ -10,9 +14,18 @@ const NUMBER_OF_NBA_TEAMS = 30
 
 var teamFilePath = "./" + hoop_watcher.TeamFileName
 
+func mustLoadTeams(t *testing.T) []hoop_watcher.NBATeam {
+	t.Helper()
+	teams, err := hoop_watcher.GetNBATeamsFromJSON(teamFilePath)
+	if err != nil {
+		t.Fatalf("could not load teams: %v", err)
+	}
+	return teams
+}
+
 func TestGetTeams(t *testing.T) {
 	t.Run("it loads teams JSON file", func(t *testing.T) {
-		teams := hoop_watcher.GetNBATeamsFromJSON(teamFilePath)
+		teams := mustLoadTeams(t)
 		if len(teams) != NUMBER_OF_NBA_TEAMS {
 			t.Errorf("expected %d number of teams but loaded %d", len(teams), NUMBER_OF_NBA_TEAMS)
 		}
@@ -26,6 +39,18 @@ func TestGetTeams(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("it returns ErrInvalidTeamsFile for a bad file", func(t *testing.T) {
+		badFile := filepath.Join(t.TempDir(), "teams.json")
+		if err := os.WriteFile(badFile, []byte("not json"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, filePath := range []string{badFile, filepath.Join(t.TempDir(), "missing.json")} {
+			if _, err := hoop_watcher.GetNBATeamsFromJSON(filePath); !errors.Is(err, hoop_watcher.ErrInvalidTeamsFile) {
+				t.Errorf("got err %v for %s, want %v", err, filePath, hoop_watcher.ErrInvalidTeamsFile)
+			}
+		}
+	})
 }
 
 type getTeamFromQueryTestCase struct {
@@ -58,7 +83,7 @@ func TestGetTeamFromQuery(t *testing.T) {
 			},
 		}
 		for _, c := range cases {
-			got := hoop_watcher.GetTeamFromQuery(c.Query, hoop_watcher.GetNBATeamsFromJSON(teamFilePath))
+			got := hoop_watcher.GetTeamFromQuery(c.Query, mustLoadTeams(t))
 			want := c.ExpectedTeamName
 			if got == nil {
 				t.Errorf("got %v want %s", got, want)
@@ -68,6 +93,26 @@ func TestGetTeamFromQuery(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("gets team from alias", func(t *testing.T) {
+		cases := []getTeamFromQueryTestCase{
+			{"Trail Blazers", "Portland Trail Blazers"},
+			{"blazers", "Portland Trail Blazers"},
+			{"Sixers", "Philadelphia 76ers"},
+			{"dubs", "Golden State Warriors"},
+			{"nola", "New Orleans Pelicans"},
+			{"LA Lakers", "Los Angeles Lakers"},
+			{"la clippers", "LA Clippers"},
+			{"NJN", "Brooklyn Nets"},
+			{"hornets 2012", "Charlotte Hornets"},
+		}
+		for _, c := range cases {
+			got := hoop_watcher.GetTeamFromQuery(c.Query, mustLoadTeams(t))
+			if got == nil || got.FullName != c.ExpectedTeamName {
+				t.Errorf("got %v want %s with query %s", got, c.ExpectedTeamName, c.Query)
+			}
+		}
+	})
 }
 
 func TestFuzzyGetTeamFromQuery(t *testing.T) {
@@ -105,9 +150,17 @@ func TestFuzzyGetTeamFromQuery(t *testing.T) {
 				"nw yrk",
 				"New York Knicks",
 			},
+			{
+				"sixers",
+				"Philadelphia 76ers",
+			},
+			{
+				"cavs",
+				"Cleveland Cavaliers",
+			},
 		}
 		for _, c := range cases {
-			got := hoop_watcher.FuzzyGetTeamFromQuery(c.Query, hoop_watcher.GetNBATeamsFromJSON(teamFilePath))
+			got := hoop_watcher.FuzzyGetTeamFromQuery(c.Query, mustLoadTeams(t))
 			want := c.ExpectedTeamName
 			if got == nil {
 				t.Errorf("got %v want %s", got, want)
@@ -118,3 +171,38 @@ func TestFuzzyGetTeamFromQuery(t *testing.T) {
 		}
 	})
 }
+
+func TestTeamAliases(t *testing.T) {
+	t.Run("every alias belongs to one known team", func(t *testing.T) {
+		abbreviations := map[string]bool{}
+		for _, team := range mustLoadTeams(t) {
+			abbreviations[team.Abbreviation] = true
+		}
+		seen := map[string]string{}
+		for abbrev, aliases := range hoop_watcher.DefaultTeamAliases {
+			if !abbreviations[abbrev] {
+				t.Errorf("aliases for unknown team %s", abbrev)
+			}
+			for _, alias := range aliases {
+				key := strings.ToLower(alias)
+				if other, ok := seen[key]; ok {
+					t.Errorf("alias %q used by both %s and %s", alias, other, abbrev)
+				}
+				seen[key] = abbrev
+			}
+		}
+	})
+
+	t.Run("no alias names another team", func(t *testing.T) {
+		teams := mustLoadTeams(t)
+		for abbrev, aliases := range hoop_watcher.DefaultTeamAliases {
+			for _, alias := range aliases {
+				for _, team := range teams {
+					if team.Abbreviation != abbrev && strings.Contains(strings.ToLower(alias), strings.ToLower(team.Name)) {
+						t.Errorf("alias %q of %s names the %s", alias, abbrev, team.FullName)
+					}
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package hoop_watcher
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/youtube/v3"
+)
+
+const defaultYoutubeMaxResults = 5
+
+// youtubeMaxVideoIds is how many video IDs videos.list accepts in one call.
+const youtubeMaxVideoIds = 50
+
+// YoutubeHighlightProvider finds highlights with the YouTube Data API search endpoint.
+type YoutubeHighlightProvider struct {
+	service    *youtube.Service
+	maxResults int64
+}
+
+func NewYoutubeHighlightProvider(service *youtube.Service) *YoutubeHighlightProvider {
+	return &YoutubeHighlightProvider{service: service, maxResults: defaultYoutubeMaxResults}
+}
+
+// searchListByQ searches for videos based on a keyword query, limited to
+// videos published in the window around date unless date is zero
+func searchListByQ(service *youtube.Service, keywordQuery string, date time.Time, maxResults int64) ([]*youtube.SearchResult, error) {
+	call := service.Search.List([]string{"id", "snippet"}).
+		Q(keywordQuery).
+		Type("video").MaxResults(maxResults)
+	if !date.IsZero() {
+		publishedAfter, publishedBefore := HighlightSearchWindow(date)
+		call = call.PublishedAfter(publishedAfter.Format(time.RFC3339)).
+			PublishedBefore(publishedBefore.Format(time.RFC3339))
+	}
+
+	response, err := call.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Items, nil
+}
+
+// videosListByIds looks up the details of videos, batching the IDs into as
+// few calls as videos.list allows
+func videosListByIds(service *youtube.Service, videoIds []string) ([]*youtube.Video, error) {
+	videos := []*youtube.Video{}
+	for start := 0; start < len(videoIds); start += youtubeMaxVideoIds {
+		end := min(start+youtubeMaxVideoIds, len(videoIds))
+		response, err := service.Videos.List([]string{"snippet", "contentDetails", "statistics"}).
+			Id(videoIds[start:end]...).
+			Do()
+		if err != nil {
+			return nil, err
+		}
+		videos = append(videos, response.Items...)
+	}
+	return videos, nil
+}
+
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseISODuration parses the ISO 8601 durations YouTube uses, e.g. PT10M32S.
+func parseISODuration(isoDuration string) (time.Duration, error) {
+	matches := isoDurationPattern.FindStringSubmatch(isoDuration)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid duration %q", isoDuration)
+	}
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if matches[i+1] == "" {
+			continue
+		}
+		value, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return 0, err
+		}
+		duration += time.Duration(value) * unit
+	}
+	return duration, nil
+}
+
+func bestThumbnailURL(thumbnails *youtube.ThumbnailDetails) string {
+	if thumbnails == nil {
+		return ""
+	}
+	for _, thumbnail := range []*youtube.Thumbnail{thumbnails.Maxres, thumbnails.High, thumbnails.Medium, thumbnails.Default} {
+		if thumbnail != nil {
+			return thumbnail.Url
+		}
+	}
+	return ""
+}
+
+// enrichHighlight copies the details of video onto highlight.
+func enrichHighlight(highlight *Highlight, video *youtube.Video) {
+	if video.ContentDetails != nil {
+		if duration, err := parseISODuration(video.ContentDetails.Duration); err == nil {
+			highlight.Duration = duration
+		}
+		highlight.Definition = video.ContentDetails.Definition
+	}
+	if video.Statistics != nil {
+		highlight.ViewCount = video.Statistics.ViewCount
+	}
+	if video.Snippet != nil {
+		if publishedAt, err := time.Parse(time.RFC3339, video.Snippet.PublishedAt); err == nil {
+			highlight.PublishedAt = publishedAt
+		}
+		highlight.Channel = video.Snippet.ChannelTitle
+		highlight.ChannelId = video.Snippet.ChannelId
+		highlight.ThumbnailURL = bestThumbnailURL(video.Snippet.Thumbnails)
+	}
+}
+
+func youtubeVideoURL(videoId string) (*url.URL, error) {
+	return url.Parse(fmt.Sprintf("https://www.youtube.com/watch?v=%v", videoId))
+}
+
+// youtubeQuotaReasons are the error reasons YouTube gives when the API key
+// has run out of quota.
+var youtubeQuotaReasons = []string{"quotaExceeded", "dailyLimitExceeded", "rateLimitExceeded", "userRateLimitExceeded"}
+
+// youtubeQuotaReset returns when the daily YouTube API quota next resets,
+// which is at midnight Pacific Time.
+func youtubeQuotaReset(now time.Time) time.Time {
+	pacific, _ := time.LoadLocation("America/Los_Angeles")
+	year, month, day := now.In(pacific).Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, pacific)
+}
+
+// youtubeError wraps a failed YouTube call in ErrQuotaExceeded or
+// ErrProviderUnavailable so callers don't need to know about googleapi.
+func youtubeError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		for _, item := range apiErr.Errors {
+			if slices.Contains(youtubeQuotaReasons, item.Reason) {
+				return fmt.Errorf("%w: %v", ErrQuotaExceeded, err)
+			}
+		}
+		if apiErr.Code == http.StatusTooManyRequests {
+			return fmt.Errorf("%w: %v", ErrQuotaExceeded, err)
+		}
+	}
+	return fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+}
+
+func (p *YoutubeHighlightProvider) SearchHighlights(query HighlightQuery) ([]Highlight, error) {
+	keywordQuery := HighlightQueryString(query.TeamNames(), query.Date, query.Kind)
+	videos, err := searchListByQ(p.service, keywordQuery, query.Date, p.maxResults)
+	if err != nil {
+		return nil, youtubeError(err)
+	}
+
+	highlights := []Highlight{}
+	videoIds := []string{}
+	for _, video := range videos {
+		videoURL, err := youtubeVideoURL(video.Id.VideoId)
+		if err != nil {
+			return nil, err
+		}
+		publishedAt, _ := time.Parse(time.RFC3339, video.Snippet.PublishedAt)
+		highlights = append(highlights, Highlight{
+			Title:       video.Snippet.Title,
+			URL:         *videoURL,
+			Channel:     video.Snippet.ChannelTitle,
+			ChannelId:   video.Snippet.ChannelId,
+			PublishedAt: publishedAt,
+		})
+		videoIds = append(videoIds, video.Id.VideoId)
+	}
+	if len(videoIds) == 0 {
+		return highlights, nil
+	}
+
+	details, err := videosListByIds(p.service, videoIds)
+	if err != nil {
+		return nil, youtubeError(err)
+	}
+	detailsById := map[string]*youtube.Video{}
+	for _, video := range details {
+		detailsById[video.Id] = video
+	}
+	for i, videoId := range videoIds {
+		if video, ok := detailsById[videoId]; ok {
+			enrichHighlight(&highlights[i], video)
+		}
+	}
+	return highlights, nil
+}
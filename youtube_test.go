@@ -0,0 +1,196 @@
+package hoop_watcher_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	hoop_watcher "github.com/WesleyT4N/hoop-watcher-cli"
+	"google.golang.org/api/option"
+	"google.golang.org/api/youtube/v3"
+)
+
+func newTestYoutubeService(t *testing.T, handler http.HandlerFunc) *youtube.Service {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	service, err := youtube.NewService(
+		context.Background(),
+		option.WithEndpoint(srv.URL),
+		option.WithAPIKey("test"),
+		option.WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatalf("could not create youtube service: %v", err)
+	}
+	return service
+}
+
+func writeSearchResponse(w http.ResponseWriter, items ...*youtube.SearchResult) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(youtube.SearchListResponse{Items: items})
+}
+
+func writeVideosResponse(w http.ResponseWriter, items ...*youtube.Video) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(youtube.VideoListResponse{Items: items})
+}
+
+func isVideosRequest(r *http.Request) bool {
+	return strings.HasSuffix(r.URL.Path, "/videos")
+}
+
+func searchResult(videoId string, title string, publishedAt string) *youtube.SearchResult {
+	return &youtube.SearchResult{
+		Id: &youtube.ResourceId{VideoId: videoId},
+		Snippet: &youtube.SearchResultSnippet{
+			Title:        title,
+			ChannelTitle: "NBA",
+			PublishedAt:  publishedAt,
+		},
+	}
+}
+
+func TestYoutubeHighlightProvider(t *testing.T) {
+	knicks := hoop_watcher.NBATeam{Name: "Knicks", FullName: "New York Knicks", Abbreviation: "NYK"}
+
+	t.Run("it searches within the publish window of the game date", func(t *testing.T) {
+		var gotQuery url.Values
+		service := newTestYoutubeService(t, func(w http.ResponseWriter, r *http.Request) {
+			if isVideosRequest(r) {
+				writeVideosResponse(w)
+				return
+			}
+			gotQuery = r.URL.Query()
+			writeSearchResponse(w, searchResult("abc", "Knicks Full Game Highlights", "2023-01-02T04:00:00Z"))
+		})
+		provider := hoop_watcher.NewYoutubeHighlightProvider(service)
+
+		date := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+		got, err := provider.SearchHighlights(hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}, Date: date})
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		if want := "'Knicks NBA Full Game Highlights January 1, 2023'"; gotQuery.Get("q") != want {
+			t.Errorf("got query %s, want %s", gotQuery.Get("q"), want)
+		}
+		if want := "2023-01-01T00:00:00Z"; gotQuery.Get("publishedAfter") != want {
+			t.Errorf("got publishedAfter %s, want %s", gotQuery.Get("publishedAfter"), want)
+		}
+		if want := "2023-01-04T00:00:00Z"; gotQuery.Get("publishedBefore") != want {
+			t.Errorf("got publishedBefore %s, want %s", gotQuery.Get("publishedBefore"), want)
+		}
+		if len(got) != 1 || got[0].URL.String() != "https://www.youtube.com/watch?v=abc" {
+			t.Fatalf("got %v, want one highlight for video abc", got)
+		}
+		if want := time.Date(2023, time.January, 2, 4, 0, 0, 0, time.UTC); !got[0].PublishedAt.Equal(want) {
+			t.Errorf("got published at %v, want %v", got[0].PublishedAt, want)
+		}
+	})
+
+	t.Run("it leaves out the date when none is given", func(t *testing.T) {
+		var gotQuery url.Values
+		service := newTestYoutubeService(t, func(w http.ResponseWriter, r *http.Request) {
+			gotQuery = r.URL.Query()
+			writeSearchResponse(w)
+		})
+		provider := hoop_watcher.NewYoutubeHighlightProvider(service)
+
+		if _, err := provider.SearchHighlights(hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}}); err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		if want := "'Knicks NBA Full Game Highlights'"; gotQuery.Get("q") != want {
+			t.Errorf("got query %s, want %s", gotQuery.Get("q"), want)
+		}
+		if gotQuery.Has("publishedAfter") || gotQuery.Has("publishedBefore") {
+			t.Errorf("expected no publish window but got %v", gotQuery)
+		}
+	})
+
+	t.Run("it enriches highlights with video details", func(t *testing.T) {
+		var gotVideoIds string
+		service := newTestYoutubeService(t, func(w http.ResponseWriter, r *http.Request) {
+			if isVideosRequest(r) {
+				gotVideoIds = strings.Join(r.URL.Query()["id"], ",")
+				writeVideosResponse(w, &youtube.Video{
+					Id: "abc",
+					Snippet: &youtube.VideoSnippet{
+						ChannelId:    "UCWJ2lWNubArHWmf3FIHbfcQ",
+						ChannelTitle: "NBA",
+						PublishedAt:  "2023-01-02T04:00:00Z",
+						Thumbnails: &youtube.ThumbnailDetails{
+							High: &youtube.Thumbnail{Url: "https://i.ytimg.com/vi/abc/hqdefault.jpg"},
+						},
+					},
+					ContentDetails: &youtube.VideoContentDetails{Duration: "PT10M32S", Definition: "hd"},
+					Statistics:     &youtube.VideoStatistics{ViewCount: 1234567},
+				})
+				return
+			}
+			writeSearchResponse(w,
+				searchResult("abc", "Knicks Full Game Highlights", "2023-01-02T04:00:00Z"),
+				searchResult("def", "Knicks Top Plays", "2023-01-02T05:00:00Z"),
+			)
+		})
+		provider := hoop_watcher.NewYoutubeHighlightProvider(service)
+
+		got, err := provider.SearchHighlights(hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}})
+		if err != nil {
+			t.Fatalf("Found err: %v", err)
+		}
+
+		if gotVideoIds != "abc,def" {
+			t.Errorf("got video ids %s, want abc,def", gotVideoIds)
+		}
+		if len(got) != 2 {
+			t.Fatalf("got %d highlights, want 2", len(got))
+		}
+		enriched := got[0]
+		if enriched.Duration != 10*time.Minute+32*time.Second {
+			t.Errorf("got duration %v, want 10m32s", enriched.Duration)
+		}
+		if enriched.ViewCount != 1234567 {
+			t.Errorf("got view count %d, want 1234567", enriched.ViewCount)
+		}
+		if enriched.ChannelId != "UCWJ2lWNubArHWmf3FIHbfcQ" || enriched.Definition != "hd" {
+			t.Errorf("got channel id %s and definition %s", enriched.ChannelId, enriched.Definition)
+		}
+		if enriched.ThumbnailURL != "https://i.ytimg.com/vi/abc/hqdefault.jpg" {
+			t.Errorf("got thumbnail %s", enriched.ThumbnailURL)
+		}
+		if got[1].Duration != 0 {
+			t.Errorf("got duration %v for a video without details, want 0", got[1].Duration)
+		}
+	})
+	t.Run("it returns typed errors when the search fails", func(t *testing.T) {
+		cases := []struct {
+			status int
+			body   string
+			want   error
+		}{
+			{http.StatusForbidden, `{"error": {"code": 403, "message": "quota", "errors": [{"reason": "quotaExceeded"}]}}`, hoop_watcher.ErrQuotaExceeded},
+			{http.StatusInternalServerError, `{"error": {"code": 500, "message": "backend error"}}`, hoop_watcher.ErrProviderUnavailable},
+		}
+		for _, c := range cases {
+			service := newTestYoutubeService(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(c.status)
+				w.Write([]byte(c.body))
+			})
+			provider := hoop_watcher.NewYoutubeHighlightProvider(service)
+
+			_, err := provider.SearchHighlights(hoop_watcher.HighlightQuery{Teams: []hoop_watcher.NBATeam{knicks}})
+			if !errors.Is(err, c.want) {
+				t.Errorf("got err %v for status %d, want %v", err, c.status, c.want)
+			}
+		}
+	})
+}